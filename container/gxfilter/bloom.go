@@ -0,0 +1,237 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxfilter
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// DefaultFalsePositiveRate is the false positive rate of a bloom filter created with an invalid rate.
+const DefaultFalsePositiveRate = 0.01
+
+// maxCount is the saturated value of the 4 bit counters of CountingBloomFilter.
+const maxCount = 15
+
+// maxHashes bounds the number of the hashes of a decoded bloom filter, each lookup computes them all.
+// bloomParams never returns more than -log2 of the min float64 rate, which is about 1075.
+const maxHashes = 2048
+
+// bloomParams returns the number of the bits m and of the hashes k of a bloom filter with the
+// false positive rate fpRate after capacity items are added.
+func bloomParams(capacity uint64, fpRate float64) (m uint64, k uint32) {
+	if capacity == 0 {
+		capacity = 1
+	}
+	if !(fpRate > 0 && fpRate < 1) {
+		fpRate = DefaultFalsePositiveRate
+	}
+	m = uint64(math.Ceil(-float64(capacity) * math.Log(fpRate) / (math.Ln2 * math.Ln2)))
+	k = uint32(math.Max(1, math.Round(float64(m)/float64(capacity)*math.Ln2)))
+	return m, k
+}
+
+// bloomHash is the double hashing of an item, the i-th of its k locations in m is (h1 + i*h2) mod m.
+type bloomHash struct {
+	h1, h2 uint64
+}
+
+func newBloomHash(item []byte) bloomHash {
+	h1 := hash(item)
+	return bloomHash{h1: h1, h2: mix(h1) | 1}
+}
+
+func (h bloomHash) location(i uint32, m uint64) uint64 {
+	return (h.h1 + uint64(i)*h.h2) % m
+}
+
+// BloomFilter is a bloom filter, it can not remove the items, use CountingBloomFilter for that.
+type BloomFilter struct {
+	bits []uint64
+	m    uint64
+	k    uint32
+}
+
+// NewBloomFilter returns a bloom filter whose false positive rate is falsePositiveRate after capacity
+// items are added, e.g. 9.6 bits an item for 0.01. The rate goes up as more items are added.
+// An invalid rate out of (0, 1) is DefaultFalsePositiveRate.
+func NewBloomFilter(capacity uint64, falsePositiveRate float64) *BloomFilter {
+	m, k := bloomParams(capacity, falsePositiveRate)
+	return &BloomFilter{bits: make([]uint64, (m+63)/64), m: m, k: k}
+}
+
+// Add adds the items to the filter.
+func (f *BloomFilter) Add(items ...[]byte) {
+	for _, item := range items {
+		h := newBloomHash(item)
+		for i := uint32(0); i < f.k; i++ {
+			loc := h.location(i, f.m)
+			f.bits[loc/64] |= 1 << (loc % 64)
+		}
+	}
+}
+
+// Contains checks whether all the items may be in the filter, it is true if there is no item.
+func (f *BloomFilter) Contains(items ...[]byte) bool {
+	for _, item := range items {
+		h := newBloomHash(item)
+		for i := uint32(0); i < f.k; i++ {
+			loc := h.location(i, f.m)
+			if f.bits[loc/64]&(1<<(loc%64)) == 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Reset removes all the items.
+func (f *BloomFilter) Reset() {
+	clear(f.bits)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (f *BloomFilter) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 0, 13+8*len(f.bits))
+	data = append(data, kindBloom)
+	data = binary.BigEndian.AppendUint64(data, f.m)
+	data = binary.BigEndian.AppendUint32(data, f.k)
+	for _, word := range f.bits {
+		data = binary.BigEndian.AppendUint64(data, word)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, it returns ErrBadEncoding if data is
+// not encoded by BloomFilter.MarshalBinary.
+func (f *BloomFilter) UnmarshalBinary(data []byte) error {
+	d := newDecoder(data, kindBloom)
+	m, k := d.uint64(), d.uint32()
+	if m == 0 || k == 0 || k > maxHashes || m/64 > uint64(len(data)) {
+		return ErrBadEncoding
+	}
+	words := d.bytes((m + 63) / 64 * 8)
+	if err := d.done(); err != nil {
+		return err
+	}
+	bits := make([]uint64, (m+63)/64)
+	for i := range bits {
+		bits[i] = binary.BigEndian.Uint64(words[i*8:])
+	}
+	*f = BloomFilter{bits: bits, m: m, k: k}
+	return nil
+}
+
+// CountingBloomFilter is a bloom filter of 4 bit counters instead of bits, so it can remove the items
+// at the cost of 4 times the memory. A counter saturated by more than 15 items is never decreased,
+// so that removing the items can not make Contains false for the others.
+type CountingBloomFilter struct {
+	counters []byte
+	m        uint64
+	k        uint32
+}
+
+// NewCountingBloomFilter returns a counting bloom filter like NewBloomFilter.
+func NewCountingBloomFilter(capacity uint64, falsePositiveRate float64) *CountingBloomFilter {
+	m, k := bloomParams(capacity, falsePositiveRate)
+	return &CountingBloomFilter{counters: make([]byte, (m+1)/2), m: m, k: k}
+}
+
+func (f *CountingBloomFilter) count(loc uint64) byte {
+	return f.counters[loc/2] >> (loc % 2 * 4) & 0xf
+}
+
+func (f *CountingBloomFilter) addCount(loc uint64, delta int) {
+	shift := loc % 2 * 4
+	count := int(f.counters[loc/2]>>shift&0xf) + delta
+	f.counters[loc/2] = f.counters[loc/2]&^(0xf<<shift) | byte(count)<<shift
+}
+
+// Add adds the items to the filter.
+func (f *CountingBloomFilter) Add(items ...[]byte) {
+	for _, item := range items {
+		h := newBloomHash(item)
+		for i := uint32(0); i < f.k; i++ {
+			if loc := h.location(i, f.m); f.count(loc) < maxCount {
+				f.addCount(loc, 1)
+			}
+		}
+	}
+}
+
+// Remove removes the items from the filter, an item not in the filter is ignored.
+func (f *CountingBloomFilter) Remove(items ...[]byte) {
+	for _, item := range items {
+		if !f.Contains(item) {
+			continue
+		}
+		h := newBloomHash(item)
+		for i := uint32(0); i < f.k; i++ {
+			if loc := h.location(i, f.m); f.count(loc) < maxCount {
+				f.addCount(loc, -1)
+			}
+		}
+	}
+}
+
+// Contains checks whether all the items may be in the filter, it is true if there is no item.
+func (f *CountingBloomFilter) Contains(items ...[]byte) bool {
+	for _, item := range items {
+		h := newBloomHash(item)
+		for i := uint32(0); i < f.k; i++ {
+			if f.count(h.location(i, f.m)) == 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Reset removes all the items.
+func (f *CountingBloomFilter) Reset() {
+	clear(f.counters)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (f *CountingBloomFilter) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 0, 13+len(f.counters))
+	data = append(data, kindCountingBloom)
+	data = binary.BigEndian.AppendUint64(data, f.m)
+	data = binary.BigEndian.AppendUint32(data, f.k)
+	return append(data, f.counters...), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, it returns ErrBadEncoding if data is
+// not encoded by CountingBloomFilter.MarshalBinary.
+func (f *CountingBloomFilter) UnmarshalBinary(data []byte) error {
+	d := newDecoder(data, kindCountingBloom)
+	m, k := d.uint64(), d.uint32()
+	if m == 0 || k == 0 || k > maxHashes || m/2 > uint64(len(data)) {
+		return ErrBadEncoding
+	}
+	counters := d.bytes((m + 1) / 2)
+	if err := d.done(); err != nil {
+		return err
+	}
+	// the unused half of the last byte must be zero.
+	if m%2 == 1 && counters[len(counters)-1]>>4 != 0 {
+		return ErrBadEncoding
+	}
+	*f = CountingBloomFilter{counters: append([]byte(nil), counters...), m: m, k: k}
+	return nil
+}
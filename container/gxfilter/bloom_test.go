@@ -0,0 +1,157 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxfilter
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func items(prefix string, n int) [][]byte {
+	items := make([][]byte, n)
+	for i := range items {
+		items[i] = []byte(fmt.Sprintf("%s-%d", prefix, i))
+	}
+	return items
+}
+
+// falsePositiveRate returns the rate of the items never added but contained by the filter.
+func falsePositiveRate(f Filter, n int) float64 {
+	positives := 0
+	for _, item := range items("absent", n) {
+		if f.Contains(item) {
+			positives++
+		}
+	}
+	return float64(positives) / float64(n)
+}
+
+func TestBloomParams(t *testing.T) {
+	m, k := bloomParams(1000, 0.01)
+	assert.Equal(t, uint64(9586), m)
+	assert.Equal(t, uint32(7), k)
+	m, k = bloomParams(0, 2)
+	assert.Equal(t, uint64(10), m)
+	assert.Equal(t, uint32(7), k)
+	_, k = bloomParams(1, math.SmallestNonzeroFloat64)
+	assert.True(t, k <= maxHashes, "k %d", k)
+}
+
+func TestBloomFilter(t *testing.T) {
+	for _, rate := range []float64{0.1, 0.01, 0.001} {
+		f := NewBloomFilter(10000, rate)
+		added := items("added", 10000)
+		f.Add(added...)
+		assert.True(t, f.Contains(added...))
+		assert.True(t, f.Contains())
+		assert.InDelta(t, rate, falsePositiveRate(f, 100000), rate/2, "rate %v", rate)
+
+		f.Reset()
+		assert.False(t, f.Contains(added[0]))
+	}
+}
+
+func TestCountingBloomFilter(t *testing.T) {
+	f := NewCountingBloomFilter(1000, 0.01)
+	var _ Filter = f
+	added := items("added", 1000)
+	f.Add(added...)
+	assert.True(t, f.Contains(added...))
+	assert.InDelta(t, 0.01, falsePositiveRate(f, 100000), 0.005)
+
+	// removing the half keeps the others.
+	f.Remove(added[:500]...)
+	assert.True(t, f.Contains(added[500:]...))
+	removed := 0
+	for _, item := range added[:500] {
+		if !f.Contains(item) {
+			removed++
+		}
+	}
+	assert.True(t, removed > 490, "removed %d", removed)
+
+	// an item added twice is removed twice, and removing an absent item is ignored.
+	f.Reset()
+	f.Add([]byte("a"), []byte("a"))
+	f.Remove([]byte("b"), []byte("a"))
+	assert.True(t, f.Contains([]byte("a")))
+	f.Remove([]byte("a"))
+	assert.False(t, f.Contains([]byte("a")))
+
+	// the saturated counters are never decreased.
+	for i := 0; i < 20; i++ {
+		f.Add([]byte("a"))
+	}
+	for i := 0; i < 20; i++ {
+		f.Remove([]byte("a"))
+	}
+	assert.True(t, f.Contains([]byte("a")))
+}
+
+func TestBloomFilterBinary(t *testing.T) {
+	filters := []struct {
+		f, to Filter
+	}{
+		{NewBloomFilter(1000, 0.01), &BloomFilter{}},
+		{NewCountingBloomFilter(999, 0.01), &CountingBloomFilter{}},
+		{NewCountingBloomFilter(10, 0.2), &CountingBloomFilter{}},
+	}
+	for _, tt := range filters {
+		added := items("added", 500)
+		tt.f.Add(added...)
+		data, err := tt.f.MarshalBinary()
+		assert.NoError(t, err)
+		assert.NoError(t, tt.to.UnmarshalBinary(data))
+		assert.Equal(t, tt.f, tt.to)
+		assert.True(t, tt.to.Contains(added...))
+
+		// too many hashes.
+		manyHashes := append([]byte(nil), data...)
+		binary.BigEndian.PutUint32(manyHashes[9:], maxHashes+1)
+		for _, bad := range [][]byte{nil, data[:len(data)-1], append(data, 0), {kindCuckoo}, data[:13], manyHashes} {
+			assert.Equal(t, ErrBadEncoding, tt.to.UnmarshalBinary(bad))
+		}
+		binary.BigEndian.PutUint32(manyHashes[9:], maxHashes)
+		assert.NoError(t, tt.to.UnmarshalBinary(manyHashes))
+	}
+}
+
+func BenchmarkBloomFilterAdd(b *testing.B) {
+	f := NewBloomFilter(uint64(b.N), 0.01)
+	item := []byte("dubbo://127.0.0.1:20000/org.apache.dubbo.UserProvider")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		f.Add(item)
+	}
+}
+
+func BenchmarkBloomFilterContains(b *testing.B) {
+	f := NewBloomFilter(1000, 0.01)
+	item := []byte("dubbo://127.0.0.1:20000/org.apache.dubbo.UserProvider")
+	f.Add(item)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		f.Contains(item)
+	}
+}
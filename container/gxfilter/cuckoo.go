@@ -0,0 +1,253 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxfilter
+
+import (
+	"encoding/binary"
+	"math/bits"
+	"math/rand"
+)
+
+const (
+	// bucketSize is the number of the fingerprints in a bucket.
+	bucketSize = 4
+	// maxKicks is the number of the relocations before an item goes to the victim.
+	maxKicks = 500
+)
+
+// CuckooFilter is a cuckoo filter of 16 bit fingerprints, whose false positive rate is about 0.012%.
+// Unlike a bloom filter, it can remove the items, and takes less memory for a low false positive rate.
+// When the buckets are nearly full, an item relocated too many times is kept as the victim, and the
+// filter is full then.
+type CuckooFilter struct {
+	fingerprints []uint16
+	mask         uint64
+	count        uint64
+	victim       cuckooVictim
+	// rng picks the fingerprints to relocate, a filter has its own source instead of the locked global one.
+	rng *rand.Rand
+}
+
+type cuckooVictim struct {
+	used        bool
+	fingerprint uint16
+	index       uint64
+}
+
+// NewCuckooFilter returns a cuckoo filter of at least capacity items.
+func NewCuckooFilter(capacity uint64) *CuckooFilter {
+	// the buckets can be filled up to 95%.
+	numBuckets := max(capacity*100/95/bucketSize+1, 1)
+	numBuckets = 1 << bits.Len64(numBuckets-1)
+	return &CuckooFilter{
+		fingerprints: make([]uint16, numBuckets*bucketSize),
+		mask:         numBuckets - 1,
+		rng:          newCuckooRand(),
+	}
+}
+
+func newCuckooRand() *rand.Rand {
+	return rand.New(rand.NewSource(rand.Int63()))
+}
+
+// random returns the random source of the filter, a decoded filter or the zero value gets one on its first use.
+func (f *CuckooFilter) random() *rand.Rand {
+	if f.rng == nil {
+		f.rng = newCuckooRand()
+	}
+	return f.rng
+}
+
+// fingerprint returns the non zero fingerprint and the first bucket of item.
+func (f *CuckooFilter) fingerprint(item []byte) (uint16, uint64) {
+	h := hash(item)
+	return uint16(h>>48%0xffff) + 1, h & f.mask
+}
+
+// altIndex returns the other bucket of the fingerprint in the bucket i.
+func (f *CuckooFilter) altIndex(i uint64, fp uint16) uint64 {
+	return (i ^ mix(uint64(fp))) & f.mask
+}
+
+func (f *CuckooFilter) bucket(i uint64) []uint16 {
+	return f.fingerprints[i*bucketSize : (i+1)*bucketSize]
+}
+
+func (f *CuckooFilter) insert(i uint64, fp uint16) bool {
+	bucket := f.bucket(i)
+	for j := range bucket {
+		if bucket[j] == 0 {
+			bucket[j] = fp
+			return true
+		}
+	}
+	return false
+}
+
+func (f *CuckooFilter) delete(i uint64, fp uint16) bool {
+	bucket := f.bucket(i)
+	for j := range bucket {
+		if bucket[j] == fp {
+			bucket[j] = 0
+			return true
+		}
+	}
+	return false
+}
+
+func (f *CuckooFilter) lookup(i uint64, fp uint16) bool {
+	for _, x := range f.bucket(i) {
+		if x == fp {
+			return true
+		}
+	}
+	return false
+}
+
+// Add adds the items to the filter, the items are dropped once the filter is full. Use TryAdd to check it.
+func (f *CuckooFilter) Add(items ...[]byte) {
+	for _, item := range items {
+		f.TryAdd(item)
+	}
+}
+
+// TryAdd adds the item to the filter, it returns false if the filter is full.
+// An item added repeatedly takes as many places, and must be removed as many times.
+func (f *CuckooFilter) TryAdd(item []byte) bool {
+	if f.victim.used {
+		return false
+	}
+	fp, i := f.fingerprint(item)
+	f.count++
+	if f.insert(i, fp) || f.insert(f.altIndex(i, fp), fp) {
+		return true
+	}
+	// relocate a random fingerprint to its other bucket to make room.
+	rng := f.random()
+	if rng.Intn(2) == 0 {
+		i = f.altIndex(i, fp)
+	}
+	for n := 0; n < maxKicks; n++ {
+		j := i*bucketSize + uint64(rng.Intn(bucketSize))
+		fp, f.fingerprints[j] = f.fingerprints[j], fp
+		i = f.altIndex(i, fp)
+		if f.insert(i, fp) {
+			return true
+		}
+	}
+	f.victim = cuckooVictim{used: true, fingerprint: fp, index: i}
+	return true
+}
+
+// Remove removes the items from the filter, an item not in the filter is ignored.
+func (f *CuckooFilter) Remove(items ...[]byte) {
+	for _, item := range items {
+		fp, i1 := f.fingerprint(item)
+		i2 := f.altIndex(i1, fp)
+		switch {
+		case f.delete(i1, fp) || f.delete(i2, fp):
+		case f.isVictim(i1, i2, fp):
+			f.victim = cuckooVictim{}
+		default:
+			continue
+		}
+		f.count--
+		// the victim takes the free place.
+		if v := f.victim; v.used && (f.insert(v.index, v.fingerprint) || f.insert(f.altIndex(v.index, v.fingerprint), v.fingerprint)) {
+			f.victim = cuckooVictim{}
+		}
+	}
+}
+
+func (f *CuckooFilter) isVictim(i1, i2 uint64, fp uint16) bool {
+	return f.victim.used && f.victim.fingerprint == fp && (f.victim.index == i1 || f.victim.index == i2)
+}
+
+// Contains checks whether all the items may be in the filter, it is true if there is no item.
+func (f *CuckooFilter) Contains(items ...[]byte) bool {
+	for _, item := range items {
+		fp, i1 := f.fingerprint(item)
+		i2 := f.altIndex(i1, fp)
+		if !f.lookup(i1, fp) && !f.lookup(i2, fp) && !f.isVictim(i1, i2, fp) {
+			return false
+		}
+	}
+	return true
+}
+
+// Count returns the number of the items in the filter.
+func (f *CuckooFilter) Count() uint64 {
+	return f.count
+}
+
+// Full checks whether the filter can not add more items.
+func (f *CuckooFilter) Full() bool {
+	return f.victim.used
+}
+
+// Reset removes all the items.
+func (f *CuckooFilter) Reset() {
+	clear(f.fingerprints)
+	f.count = 0
+	f.victim = cuckooVictim{}
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (f *CuckooFilter) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 0, 28+2*len(f.fingerprints))
+	data = append(data, kindCuckoo)
+	data = binary.BigEndian.AppendUint64(data, f.mask+1)
+	data = binary.BigEndian.AppendUint64(data, f.count)
+	if f.victim.used {
+		data = append(data, 1)
+	} else {
+		data = append(data, 0)
+	}
+	data = binary.BigEndian.AppendUint16(data, f.victim.fingerprint)
+	data = binary.BigEndian.AppendUint64(data, f.victim.index)
+	for _, fp := range f.fingerprints {
+		data = binary.BigEndian.AppendUint16(data, fp)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, it returns ErrBadEncoding if data is
+// not encoded by CuckooFilter.MarshalBinary.
+func (f *CuckooFilter) UnmarshalBinary(data []byte) error {
+	d := newDecoder(data, kindCuckoo)
+	numBuckets, count := d.uint64(), d.uint64()
+	used := d.next(1)[0]
+	victim := cuckooVictim{used: used == 1, fingerprint: d.uint16(), index: d.uint64()}
+	// an unused victim is all zeros.
+	validVictim := used == 1 && victim.fingerprint != 0 && victim.index < numBuckets ||
+		used == 0 && victim.fingerprint == 0 && victim.index == 0
+	if numBuckets == 0 || numBuckets&(numBuckets-1) != 0 || numBuckets > uint64(len(data)) ||
+		!validVictim || count > numBuckets*bucketSize+1 {
+		return ErrBadEncoding
+	}
+	b := d.bytes(numBuckets * bucketSize * 2)
+	if err := d.done(); err != nil {
+		return err
+	}
+	fingerprints := make([]uint16, numBuckets*bucketSize)
+	for i := range fingerprints {
+		fingerprints[i] = binary.BigEndian.Uint16(b[i*2:])
+	}
+	*f = CuckooFilter{fingerprints: fingerprints, mask: numBuckets - 1, count: count, victim: victim, rng: f.rng}
+	return nil
+}
@@ -0,0 +1,137 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxfilter
+
+import (
+	"math/rand"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCuckooFilter(t *testing.T) {
+	f := NewCuckooFilter(10000)
+	assert.Equal(t, 4096*bucketSize, len(f.fingerprints))
+	added := items("added", 10000)
+	for _, item := range added {
+		assert.True(t, f.TryAdd(item))
+	}
+	assert.Equal(t, uint64(10000), f.Count())
+	assert.True(t, f.Contains(added...))
+	assert.InDelta(t, 0.0002, falsePositiveRate(f, 100000), 0.0002)
+
+	f.Remove(added[:5000]...)
+	f.Remove([]byte("absent"))
+	assert.Equal(t, uint64(5000), f.Count())
+	assert.True(t, f.Contains(added[5000:]...))
+	removed := 0
+	for _, item := range added[:5000] {
+		if !f.Contains(item) {
+			removed++
+		}
+	}
+	assert.True(t, removed > 4990, "removed %d", removed)
+
+	f.Reset()
+	assert.Equal(t, uint64(0), f.Count())
+	assert.False(t, f.Contains(added[0]))
+}
+
+func TestCuckooFilterFull(t *testing.T) {
+	f := NewCuckooFilter(100)
+	added := items("added", 1000)
+	n := 0
+	for ; n < len(added) && f.TryAdd(added[n]); n++ {
+	}
+	// the buckets are filled over 90% before the filter is full.
+	assert.True(t, f.Full())
+	assert.True(t, n > len(f.fingerprints)*9/10, "added %d", n)
+	assert.Equal(t, uint64(n), f.Count())
+	assert.True(t, f.Contains(added[:n]...))
+	assert.False(t, f.TryAdd(added[n]))
+
+	// the victim moves into a place freed in one of its buckets.
+	removed := 0
+	for ; f.Full(); removed++ {
+		f.Remove(added[removed])
+	}
+	assert.True(t, f.Contains(added[removed:n]...))
+	assert.Equal(t, uint64(n-removed), f.Count())
+	assert.True(t, f.TryAdd(added[n]))
+}
+
+func TestCuckooFilterRand(t *testing.T) {
+	f, g := NewCuckooFilter(100), NewCuckooFilter(100)
+	assert.NotSame(t, f.rng, g.rng)
+
+	// the zero value and the decoded filters get a source on the first relocation.
+	data, err := NewCuckooFilter(100).MarshalBinary()
+	assert.NoError(t, err)
+	var to CuckooFilter
+	assert.NoError(t, to.UnmarshalBinary(data))
+	added := items("added", 1000)
+	to.Add(added...)
+	assert.NotNil(t, to.rng)
+	assert.True(t, to.Full())
+	rng := to.rng
+	assert.NoError(t, to.UnmarshalBinary(data))
+	assert.Same(t, rng, to.rng)
+
+	// a seeded source relocates the same way every time.
+	filled := func() []uint16 {
+		f := NewCuckooFilter(100)
+		f.rng = rand.New(rand.NewSource(1))
+		f.Add(added...)
+		return f.fingerprints
+	}
+	assert.Equal(t, filled(), filled())
+}
+
+func TestCuckooFilterBinary(t *testing.T) {
+	f := NewCuckooFilter(100)
+	added := items("added", 1000)
+	f.Add(added...)
+	assert.True(t, f.Full())
+
+	data, err := f.MarshalBinary()
+	assert.NoError(t, err)
+	var to CuckooFilter
+	assert.NoError(t, to.UnmarshalBinary(data))
+	// the random source is not encoded.
+	assert.Nil(t, to.rng)
+	to.rng = f.rng
+	assert.Equal(t, *f, to)
+
+	bad := append([]byte(nil), data...)
+	bad[1] = 3 // the number of the buckets is not a power of 2.
+	for _, bad := range [][]byte{nil, data[:len(data)-1], append(data, 0), {kindBloom}, bad} {
+		assert.Equal(t, ErrBadEncoding, to.UnmarshalBinary(bad))
+	}
+}
+
+func BenchmarkCuckooFilterContains(b *testing.B) {
+	f := NewCuckooFilter(1000)
+	item := []byte("dubbo://127.0.0.1:20000/org.apache.dubbo.UserProvider")
+	f.Add(item)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		f.Contains(item)
+	}
+}
@@ -0,0 +1,140 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package gxfilter provides the probabilistic sets of the byte strings, which take bounded memory
+// at the cost of false positives: Contains may return true for an item never added, but never false
+// for an added one.
+package gxfilter
+
+import (
+	"encoding"
+	"encoding/binary"
+)
+
+import (
+	"github.com/pkg/errors"
+)
+
+import (
+	"github.com/dubbogo/gost/container/gxset"
+)
+
+// ErrBadEncoding is returned when the binary of a filter is malformed.
+var ErrBadEncoding = errors.Errorf("gxfilter: bad filter encoding")
+
+// Filter is implemented by the filters of this package. The binary encoding of a filter can be shipped
+// to another node and decoded there, the items are hashed the same way in every process.
+// The filters are not safe for concurrent use.
+type Filter interface {
+	Add(items ...[]byte)
+	Contains(items ...[]byte) bool
+	Reset()
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+}
+
+// DeletableFilter is a filter that can remove the added items, it shares the membership operations of gxset.
+// Removing an item never added may remove another item sharing its hash.
+type DeletableFilter interface {
+	Filter
+	gxset.Membership[[]byte]
+}
+
+var (
+	_ Filter          = (*BloomFilter)(nil)
+	_ DeletableFilter = (*CountingBloomFilter)(nil)
+	_ DeletableFilter = (*CuckooFilter)(nil)
+)
+
+// the kinds of the filters in the first byte of the binary encoding.
+const (
+	kindBloom byte = iota + 1
+	kindCountingBloom
+	kindCuckoo
+)
+
+// hash returns the 64 bit FNV-1a hash of item mixed to spread the short items over all the bits.
+func hash(item []byte) uint64 {
+	h := uint64(14695981039346656037)
+	for _, c := range item {
+		h ^= uint64(c)
+		h *= 1099511628211
+	}
+	return mix(h)
+}
+
+// mix is the finalizer of splitmix64.
+func mix(h uint64) uint64 {
+	h ^= h >> 30
+	h *= 0xbf58476d1ce4e5b9
+	h ^= h >> 27
+	h *= 0x94d049bb133111eb
+	h ^= h >> 31
+	return h
+}
+
+// decoder reads the big endian fields of a binary encoding.
+type decoder struct {
+	data []byte
+	err  error
+}
+
+func newDecoder(data []byte, kind byte) *decoder {
+	if len(data) == 0 || data[0] != kind {
+		return &decoder{err: ErrBadEncoding}
+	}
+	return &decoder{data: data[1:]}
+}
+
+// bytes returns the next n bytes, or nil if there are not so many.
+func (d *decoder) bytes(n uint64) []byte {
+	if d.err != nil || uint64(len(d.data)) < n {
+		d.err = ErrBadEncoding
+		return nil
+	}
+	b := d.data[:n]
+	d.data = d.data[n:]
+	return b
+}
+
+// next returns the next n bytes of a field, or zeros if there are not so many.
+func (d *decoder) next(n uint64) []byte {
+	if b := d.bytes(n); b != nil {
+		return b
+	}
+	return make([]byte, n)
+}
+
+func (d *decoder) uint16() uint16 {
+	return binary.BigEndian.Uint16(d.next(2))
+}
+
+func (d *decoder) uint32() uint32 {
+	return binary.BigEndian.Uint32(d.next(4))
+}
+
+func (d *decoder) uint64() uint64 {
+	return binary.BigEndian.Uint64(d.next(8))
+}
+
+// done returns the error of the decoding, the data must be consumed up.
+func (d *decoder) done() error {
+	if d.err == nil && len(d.data) > 0 {
+		d.err = ErrBadEncoding
+	}
+	return d.err
+}
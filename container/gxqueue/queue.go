@@ -18,6 +18,7 @@ limitations under the License.
 package gxqueue
 
 import (
+	"context"
 	"errors"
 	"runtime"
 	"sync"
@@ -70,15 +71,15 @@ func (w *waiters) remove(sema *sema) {
 }
 
 // items is the struct responsible for store queue data
-type items []interface{}
+type items[T any] []T
 
-func (items *items) get(number int64) []interface{} {
+func (items *items[T]) get(number int64) []T {
 	index := int(number)
 	if int(number) > len(*items) {
 		index = len(*items)
 	}
 
-	returnItems := make([]interface{}, 0, index)
+	returnItems := make([]T, 0, index)
 	returnItems = returnItems[:index]
 
 	copy(returnItems[:index], (*items))
@@ -87,24 +88,26 @@ func (items *items) get(number int64) []interface{} {
 	return returnItems
 }
 
-func (items *items) peek() (interface{}, bool) {
+func (items *items[T]) peek() (T, bool) {
 	if len(*items) == 0 {
-		return nil, false
+		var zero T
+		return zero, false
 	}
 
 	return (*items)[0], true
 }
 
-func (items *items) getUntil(checker func(item interface{}) bool) []interface{} {
+func (items *items[T]) getUntil(checker func(item T) bool) []T {
 	length := len(*items)
 
 	if len(*items) == 0 {
 		// returning nil here actually wraps that nil in a list
 		// of interfaces... thanks go
-		return []interface{}{}
+		return []T{}
 	}
 
-	returnItems := make([]interface{}, 0, length)
+	var zero T
+	returnItems := make([]T, 0, length)
 	index := -1
 	for i, item := range *items {
 		if !checker(item) {
@@ -113,7 +116,7 @@ func (items *items) getUntil(checker func(item interface{}) bool) []interface{}
 
 		returnItems = append(returnItems, item)
 		index = i
-		(*items)[i] = nil // prevent memory leak
+		(*items)[i] = zero // prevent memory leak
 	}
 
 	*items = (*items)[index+1:]
@@ -134,24 +137,33 @@ func newSema() *sema {
 	}
 }
 
-// Queue is the struct responsible for tracking the state
-// of the queue.
-type Queue struct {
+// TypedQueue is the struct responsible for tracking the state
+// of the queue of the items of type T.
+type TypedQueue[T any] struct {
 	waiters  waiters
-	items    items
+	items    items[T]
 	lock     sync.Mutex
 	disposed int32
 }
 
-// New is a constructor for a new threadsafe queue.
+// Queue is the queue of the items of any type. Prefer a TypedQueue,
+// which saves boxing every item into an interface{}.
+type Queue = TypedQueue[interface{}]
+
+// New is a constructor for a new threadsafe queue of the items of any type.
 func New(hint int64) *Queue {
-	return &Queue{
-		items: make([]interface{}, 0, hint),
+	return NewTypedQueue[interface{}](hint)
+}
+
+// NewTypedQueue is a constructor for a new threadsafe queue of the items of type T.
+func NewTypedQueue[T any](hint int64) *TypedQueue[T] {
+	return &TypedQueue[T]{
+		items: make([]T, 0, hint),
 	}
 }
 
 // Put will add the specified items to the queue.
-func (q *Queue) Put(items ...interface{}) error {
+func (q *TypedQueue[T]) Put(items ...T) error {
 	if len(items) == 0 {
 		return nil
 	}
@@ -188,19 +200,36 @@ func (q *Queue) Put(items ...interface{}) error {
 // queue, get will return a number UP TO the number passed in as a
 // parameter.  If no items are in the queue, this method will pause
 // until items are added to the queue.
-func (q *Queue) Get(number int64) ([]interface{}, error) {
+func (q *TypedQueue[T]) Get(number int64) ([]T, error) {
 	return q.Poll(number, 0)
 }
 
+// GetContext is Get that stops waiting once ctx is done, and returns
+// the error of ctx then.
+func (q *TypedQueue[T]) GetContext(ctx context.Context, number int64) ([]T, error) {
+	return q.PollContext(ctx, number)
+}
+
 // Poll retrieves items from the queue.  If there are some items in the queue,
 // Poll will return a number UP TO the number passed in as a parameter.  If no
 // items are in the queue, this method will pause until items are added to the
 // queue or the provided timeout is reached.  A non-positive timeout will block
 // until items are added.  If a timeout occurs, ErrTimeout is returned.
-func (q *Queue) Poll(number int64, timeout time.Duration) ([]interface{}, error) {
+func (q *TypedQueue[T]) Poll(number int64, timeout time.Duration) ([]T, error) {
+	return q.poll(context.Background(), number, timeout)
+}
+
+// PollContext is Poll whose timeout is the deadline of ctx.  If no items are
+// in the queue, it will pause until items are added to the queue or ctx is
+// done, and returns the error of ctx then, e.g. context.DeadlineExceeded.
+func (q *TypedQueue[T]) PollContext(ctx context.Context, number int64) ([]T, error) {
+	return q.poll(ctx, number, 0)
+}
+
+func (q *TypedQueue[T]) poll(ctx context.Context, number int64, timeout time.Duration) ([]T, error) {
 	if number < 1 {
 		// thanks again go
-		return []interface{}{}, nil
+		return []T{}, nil
 	}
 
 	q.lock.Lock()
@@ -210,41 +239,17 @@ func (q *Queue) Poll(number int64, timeout time.Duration) ([]interface{}, error)
 		return nil, ErrDisposed
 	}
 
-	var items []interface{}
+	var items []T
 
 	if len(q.items) == 0 {
-		sema := newSema()
-		q.waiters.put(sema)
-		q.lock.Unlock()
-
-		var timeoutC <-chan time.Time
-		if timeout > 0 {
-			timeoutC = time.After(timeout)
-		}
-		select {
-		case <-sema.ready:
-			// we are now inside the put's lock
-			if atomic.LoadInt32(&q.disposed) == 1 {
-				return nil, ErrDisposed
-			}
-			items = q.items.get(number)
-			sema.response.Done()
-			return items, nil
-		case <-timeoutC:
-			// cleanup the sema that was added to waiters
-			select {
-			case sema.ready <- true:
-				// we called this before Put() could
-				// Remove sema from waiters.
-				q.lock.Lock()
-				q.waiters.remove(sema)
-				q.lock.Unlock()
-			default:
-				// Put() got it already, we need to call Done() so Put() can move on
-				sema.response.Done()
-			}
-			return nil, ErrTimeout
+		sema, err := q.wait(ctx, timeout)
+		if err != nil {
+			return nil, err
 		}
+		// we are now inside the put's lock
+		items = q.items.get(number)
+		sema.response.Done()
+		return items, nil
 	}
 
 	items = q.items.get(number)
@@ -252,28 +257,102 @@ func (q *Queue) Poll(number int64, timeout time.Duration) ([]interface{}, error)
 	return items, nil
 }
 
+// wait is called with the lock held when the queue is empty, it adds a sema
+// to waiters and releases the lock, then pauses until Put or Dispose releases
+// the sema, the timeout is reached or ctx is done.  Without an error, it
+// returns inside the put's lock, and the caller must call response.Done() of
+// the sema after reading the items.  Otherwise the sema is removed from waiters.
+func (q *TypedQueue[T]) wait(ctx context.Context, timeout time.Duration) (*sema, error) {
+	sema := newSema()
+	q.waiters.put(sema)
+	q.lock.Unlock()
+
+	var timeoutC <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		timeoutC = timer.C
+	}
+
+	var err error
+	select {
+	case <-sema.ready:
+		if atomic.LoadInt32(&q.disposed) == 1 {
+			return nil, ErrDisposed
+		}
+		return sema, nil
+	case <-timeoutC:
+		err = ErrTimeout
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	// cleanup the sema that was added to waiters
+	select {
+	case sema.ready <- true:
+		// we called this before Put() could
+		// Remove sema from waiters.
+		q.lock.Lock()
+		q.waiters.remove(sema)
+		q.lock.Unlock()
+	default:
+		// Put() got it already, we need to call Done() so Put() can move on
+		sema.response.Done()
+	}
+	return nil, err
+}
+
 // Peek returns a the first item in the queue by value
 // without modifying the queue.
-func (q *Queue) Peek() (interface{}, error) {
+func (q *TypedQueue[T]) Peek() (T, error) {
 	q.lock.Lock()
 	defer q.lock.Unlock()
 
+	var zero T
 	if atomic.LoadInt32(&q.disposed) == 1 {
-		return nil, ErrDisposed
+		return zero, ErrDisposed
 	}
 
 	peekItem, ok := q.items.peek()
 	if !ok {
-		return nil, ErrEmptyQueue
+		return zero, ErrEmptyQueue
+	}
+
+	return peekItem, nil
+}
+
+// PeekWait returns the first item in the queue by value without modifying
+// the queue.  If no items are in the queue, it will pause until items are
+// added to the queue or ctx is done, and returns the error of ctx then.
+func (q *TypedQueue[T]) PeekWait(ctx context.Context) (T, error) {
+	var zero T
+	q.lock.Lock()
+
+	if atomic.LoadInt32(&q.disposed) == 1 {
+		q.lock.Unlock()
+		return zero, ErrDisposed
 	}
 
+	if peekItem, ok := q.items.peek(); ok {
+		q.lock.Unlock()
+		return peekItem, nil
+	}
+
+	sema, err := q.wait(ctx, 0)
+	if err != nil {
+		return zero, err
+	}
+	// we are now inside the put's lock, which goes on releasing
+	// the other waiters as the item is left in the queue.
+	peekItem, _ := q.items.peek()
+	sema.response.Done()
 	return peekItem, nil
 }
 
 // GetUntil gets a function and returns a list of items that
 // match the checker until the checker returns false.  This does not
 // wait if there are no items in the queue.
-func (q *Queue) GetUntil(checker func(item interface{}) bool) ([]interface{}, error) {
+func (q *TypedQueue[T]) GetUntil(checker func(item T) bool) ([]T, error) {
 	if checker == nil {
 		return nil, nil
 	}
@@ -291,7 +370,7 @@ func (q *Queue) GetUntil(checker func(item interface{}) bool) ([]interface{}, er
 }
 
 // Empty returns a bool indicating if this bool is empty.
-func (q *Queue) Empty() bool {
+func (q *TypedQueue[T]) Empty() bool {
 	q.lock.Lock()
 	defer q.lock.Unlock()
 
@@ -299,7 +378,7 @@ func (q *Queue) Empty() bool {
 }
 
 // Len returns the number of items in this queue.
-func (q *Queue) Len() int64 {
+func (q *TypedQueue[T]) Len() int64 {
 	q.lock.Lock()
 	defer q.lock.Unlock()
 
@@ -308,7 +387,7 @@ func (q *Queue) Len() int64 {
 
 // Disposed returns a bool indicating if this queue
 // has had disposed called on it.
-func (q *Queue) Disposed() bool {
+func (q *TypedQueue[T]) Disposed() bool {
 	q.lock.Lock()
 	defer q.lock.Unlock()
 
@@ -318,7 +397,7 @@ func (q *Queue) Disposed() bool {
 // Dispose will dispose of this queue and returns
 // the items disposed. Any subsequent calls to Get
 // or Put will return an error.
-func (q *Queue) Dispose() []interface{} {
+func (q *TypedQueue[T]) Dispose() []T {
 	q.lock.Lock()
 	defer q.lock.Unlock()
 
@@ -345,7 +424,7 @@ func (q *Queue) Dispose() []interface{} {
 // with each item in the queue until the queue is exhausted.  When the queue
 // is exhausted execution is complete and all goroutines will be killed.
 // This means that the queue will be disposed so cannot be used again.
-func ExecuteInParallel(q *Queue, fn func(interface{})) {
+func ExecuteInParallel[T any](q *TypedQueue[T], fn func(T)) {
 	if q == nil {
 		return
 	}
@@ -366,6 +445,7 @@ func ExecuteInParallel(q *Queue, fn func(interface{})) {
 	var wg sync.WaitGroup
 	wg.Add(numCPU)
 	items := q.items
+	var zero T
 
 	for i := 0; i < numCPU; i++ {
 		go func() {
@@ -377,7 +457,7 @@ func ExecuteInParallel(q *Queue, fn func(interface{})) {
 				}
 
 				fn(items[index])
-				items[index] = 0
+				items[index] = zero
 			}
 		}()
 	}
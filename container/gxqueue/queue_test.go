@@ -18,6 +18,7 @@ limitations under the License.
 package gxqueue
 
 import (
+	"context"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -613,3 +614,177 @@ func BenchmarkExecuteInParallel(b *testing.B) {
 		ExecuteInParallel(q, fn)
 	}
 }
+
+func TestTypedQueue(t *testing.T) {
+	q := NewTypedQueue[int](10)
+
+	_, err := q.Peek()
+	assert.Equal(t, ErrEmptyQueue, err)
+	_, err = q.Poll(1, time.Millisecond)
+	assert.Equal(t, ErrTimeout, err)
+
+	// a waiter is released by Put.
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		result, err := q.Get(2)
+		assert.Nil(t, err)
+		assert.Equal(t, []int{1}, result)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	assert.Nil(t, q.Put(1))
+	wg.Wait()
+
+	assert.Nil(t, q.Put(2, 3, 4))
+	peekItem, err := q.Peek()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, peekItem)
+	result, err := q.GetUntil(func(item int) bool {
+		return item < 4
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []int{2, 3}, result)
+
+	assert.Equal(t, []int{4}, q.Dispose())
+	assert.Equal(t, ErrDisposed, q.Put(5))
+	_, err = q.Peek()
+	assert.Equal(t, ErrDisposed, err)
+
+	var counter int64
+	q = NewTypedQueue[int](10)
+	q.Put(1, 2, 3)
+	ExecuteInParallel(q, func(item int) {
+		atomic.AddInt64(&counter, int64(item))
+	})
+	assert.Equal(t, int64(6), counter)
+	assert.True(t, q.Disposed())
+}
+
+// BenchmarkQueueBatch puts and gets the batches of int64 through the queues of interface{} and int64,
+// the typed queue saves boxing every item.
+func BenchmarkQueueBatch(b *testing.B) {
+	const batchSize = 100
+	b.Run("Any", func(b *testing.B) {
+		b.ReportAllocs()
+		q := New(batchSize)
+		batch := make([]interface{}, batchSize)
+		for i := 0; i < b.N; i++ {
+			for j := range batch {
+				batch[j] = int64(i*batchSize + j)
+			}
+			q.Put(batch...)
+			items, _ := q.Get(batchSize)
+			for _, item := range items {
+				_ = item.(int64)
+			}
+		}
+	})
+	b.Run("Typed", func(b *testing.B) {
+		b.ReportAllocs()
+		q := NewTypedQueue[int64](batchSize)
+		batch := make([]int64, batchSize)
+		for i := 0; i < b.N; i++ {
+			for j := range batch {
+				batch[j] = int64(i*batchSize + j)
+			}
+			q.Put(batch...)
+			items, _ := q.Get(batchSize)
+			for _, item := range items {
+				_ = item
+			}
+		}
+	})
+}
+
+func TestGetContext(t *testing.T) {
+	q := NewTypedQueue[string](10)
+
+	// the waiter is removed from waiters on cancel.
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	_, err := q.GetContext(ctx, 1)
+	assert.Equal(t, context.Canceled, err)
+	assert.Len(t, q.waiters, 0)
+
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = q.PollContext(ctx, 1)
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Len(t, q.waiters, 0)
+
+	// the items put before the deadline are returned.
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		q.Put(`a`, `b`)
+	}()
+	result, err := q.GetContext(ctx, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{`a`}, result)
+	result, err = q.GetContext(ctx, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{`b`}, result)
+
+	// the queue still works after the canceled waiters.
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		q.Put(`c`)
+	}()
+	result, err = q.Get(1)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{`c`}, result)
+
+	q.Dispose()
+	_, err = q.GetContext(ctx, 1)
+	assert.Equal(t, ErrDisposed, err)
+}
+
+func TestPeekWait(t *testing.T) {
+	q := NewTypedQueue[string](10)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := q.PeekWait(ctx)
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Len(t, q.waiters, 0)
+
+	// a Put releases both the peeking and the getting waiters.
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		peekItem, err := q.PeekWait(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, `a`, peekItem)
+	}()
+	go func() {
+		defer wg.Done()
+		time.Sleep(5 * time.Millisecond)
+		result, err := q.Get(1)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{`a`}, result)
+	}()
+	time.Sleep(20 * time.Millisecond)
+	q.Put(`a`)
+	wg.Wait()
+
+	q.Put(`b`)
+	peekItem, err := q.PeekWait(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, `b`, peekItem)
+	assert.Equal(t, int64(1), q.Len())
+
+	// Dispose releases the waiter.
+	q.Get(1)
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		q.Dispose()
+	}()
+	_, err = q.PeekWait(context.Background())
+	assert.Equal(t, ErrDisposed, err)
+}
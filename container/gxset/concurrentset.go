@@ -0,0 +1,113 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxset
+
+import (
+	"iter"
+	"sync"
+	"sync/atomic"
+)
+
+// ConcurrentSet is a set of the items of type T safe for concurrent use, it is backed by a sync.Map,
+// so it suits the sets mostly read, or written by the goroutines of the disjoint items.
+// The zero value is an empty set ready to use, and it must not be copied after the first use.
+type ConcurrentSet[T comparable] struct {
+	items sync.Map
+	size  atomic.Int64
+}
+
+// NewConcurrentSet returns a concurrent set of values.
+func NewConcurrentSet[T comparable](values ...T) *ConcurrentSet[T] {
+	set := &ConcurrentSet[T]{}
+	set.Add(values...)
+	return set
+}
+
+// Add adds the items to the set.
+func (set *ConcurrentSet[T]) Add(items ...T) {
+	for _, item := range items {
+		if _, loaded := set.items.LoadOrStore(item, itemExists); !loaded {
+			set.size.Add(1)
+		}
+	}
+}
+
+// Remove removes the items from the set.
+func (set *ConcurrentSet[T]) Remove(items ...T) {
+	for _, item := range items {
+		if _, loaded := set.items.LoadAndDelete(item); loaded {
+			set.size.Add(-1)
+		}
+	}
+}
+
+// Contains checks whether all the items are in the set, it is true if there is no item.
+func (set *ConcurrentSet[T]) Contains(items ...T) bool {
+	for _, item := range items {
+		if _, contains := set.items.Load(item); !contains {
+			return false
+		}
+	}
+	return true
+}
+
+// Empty checks whether the set has no item.
+func (set *ConcurrentSet[T]) Empty() bool {
+	return set.Size() == 0
+}
+
+// Size returns the number of the items, it may be stale while the set is being modified.
+func (set *ConcurrentSet[T]) Size() int {
+	return int(set.size.Load())
+}
+
+// Clear removes all the items, the ones added during the call may be kept.
+func (set *ConcurrentSet[T]) Clear() {
+	set.items.Range(func(item, _ interface{}) bool {
+		set.Remove(item.(T))
+		return true
+	})
+}
+
+// Values returns a snapshot of the items in no particular order.
+func (set *ConcurrentSet[T]) Values() []T {
+	values := make([]T, 0, set.Size())
+	set.Range(func(item T) bool {
+		values = append(values, item)
+		return true
+	})
+	return values
+}
+
+// Range calls f for each item in no particular order until f returns false.
+// Like sync.Map.Range, the set can be modified by f and the other goroutines, an item added or removed
+// during the call may be visited or not.
+func (set *ConcurrentSet[T]) Range(f func(item T) bool) {
+	set.items.Range(func(item, _ interface{}) bool {
+		return f(item.(T))
+	})
+}
+
+// All returns an iterator over the items in no particular order.
+func (set *ConcurrentSet[T]) All() iter.Seq[T] {
+	return set.Range
+}
+
+func (set *ConcurrentSet[T]) String() string {
+	return setString("ConcurrentSet", set.Range)
+}
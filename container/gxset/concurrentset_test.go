@@ -0,0 +1,76 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxset
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestConcurrentSet(t *testing.T) {
+	var set ConcurrentSet[int]
+	set.Add(1, 2, 2)
+	if actualValue := set.Size(); actualValue != 2 {
+		t.Errorf("Got %v expected %v", actualValue, 2)
+	}
+	if actualValue := set.Contains(1, 2); actualValue != true {
+		t.Errorf("Got %v expected %v", actualValue, true)
+	}
+	set.Remove(2, 3)
+	if actualValue := set.Size(); actualValue != 1 {
+		t.Errorf("Got %v expected %v", actualValue, 1)
+	}
+	if actualValue := set.String(); actualValue != "ConcurrentSet\n1" {
+		t.Errorf("Got %v expected %v", actualValue, "ConcurrentSet\n1")
+	}
+	set.Clear()
+	if actualValue := set.Empty(); actualValue != true {
+		t.Errorf("Got %v expected %v", actualValue, true)
+	}
+}
+
+func TestConcurrentSetParallel(t *testing.T) {
+	set := NewConcurrentSet[int]()
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for n := 0; n < 1000; n++ {
+				set.Add(n)
+				set.Contains(n)
+				if n%2 == 1 {
+					set.Remove(n)
+				}
+				if n%100 == 0 {
+					set.Range(func(int) bool { return true })
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	// every goroutine removes the odd items after adding them.
+	for n := 0; n < 1000; n++ {
+		if actualValue := set.Contains(n); actualValue != (n%2 == 0) {
+			t.Errorf("item %v: Got %v expected %v", n, actualValue, n%2 == 0)
+		}
+	}
+	if actualValue := set.Size(); actualValue != 500 || len(set.Values()) != 500 {
+		t.Errorf("Got %v expected %v", actualValue, 500)
+	}
+}
@@ -18,12 +18,13 @@
 package gxset
 
 import (
-	"fmt"
-	"strings"
+	"iter"
 )
 
 var itemExists = struct{}{}
 
+// HashSet is a set of the items of any hashable type, it is kept for compatibility,
+// use the type-safe Set instead. An unhashable item like a slice panics.
 type HashSet struct {
 	Items map[interface{}]struct{}
 }
@@ -36,29 +37,30 @@ func NewSet(values ...interface{}) *HashSet {
 	return set
 }
 
+// set returns the Set sharing the items of the HashSet.
+func (set *HashSet) set() *Set[interface{}] {
+	return &Set[interface{}]{items: set.Items}
+}
+
 func (set *HashSet) Add(items ...interface{}) {
-	for _, item := range items {
-		set.Items[item] = itemExists
+	if set.Items == nil {
+		set.Items = make(map[interface{}]struct{})
 	}
+	set.set().Add(items...)
 }
 
 func (set *HashSet) Remove(items ...interface{}) {
-	for _, item := range items {
-		delete(set.Items, item)
-	}
+	set.set().Remove(items...)
 }
 
 func (set *HashSet) Contains(items ...interface{}) bool {
-	for _, item := range items {
-		if _, contains := set.Items[item]; !contains {
-			return false
-		}
-	}
-	return true
+	return set.set().Contains(items...)
 }
+
 func (set *HashSet) Empty() bool {
 	return set.Size() == 0
 }
+
 func (set *HashSet) Size() int {
 	return len(set.Items)
 }
@@ -68,20 +70,95 @@ func (set *HashSet) Clear() {
 }
 
 func (set *HashSet) Values() []interface{} {
-	values := make([]interface{}, set.Size())
-	count := 0
-	for item := range set.Items {
-		values[count] = item
-		count++
-	}
-	return values
+	return set.set().Values()
+}
+
+// Range calls f for each item in no particular order until f returns false.
+func (set *HashSet) Range(f func(item interface{}) bool) {
+	set.set().Range(f)
+}
+
+// All returns an iterator over the items in no particular order.
+func (set *HashSet) All() iter.Seq[interface{}] {
+	return set.Range
 }
+
 func (set *HashSet) String() string {
-	str := "HashSet\n"
-	var items []string
-	for k := range set.Items {
-		items = append(items, fmt.Sprintf("%v", k))
+	return setString("HashSet", set.Range)
+}
+
+// Clone returns a copy of the set.
+func (set *HashSet) Clone() *HashSet {
+	return &HashSet{Items: set.set().Clone().items}
+}
+
+// Union returns the items in either set.
+func (set *HashSet) Union(other *HashSet) *HashSet {
+	return &HashSet{Items: set.set().Union(other.set()).items}
+}
+
+// Intersection returns the items in both sets.
+func (set *HashSet) Intersection(other *HashSet) *HashSet {
+	return &HashSet{Items: set.set().Intersection(other.set()).items}
+}
+
+// Difference returns the items in the set but not in other.
+func (set *HashSet) Difference(other *HashSet) *HashSet {
+	return &HashSet{Items: set.set().Difference(other.set()).items}
+}
+
+// SymmetricDifference returns the items in exactly one of the sets.
+func (set *HashSet) SymmetricDifference(other *HashSet) *HashSet {
+	return &HashSet{Items: set.set().SymmetricDifference(other.set()).items}
+}
+
+// UnionWith adds the items of other to the set.
+func (set *HashSet) UnionWith(other *HashSet) {
+	set.update(other, (*Set[interface{}]).UnionWith)
+}
+
+// IntersectWith removes the items not in other from the set.
+func (set *HashSet) IntersectWith(other *HashSet) {
+	set.update(other, (*Set[interface{}]).IntersectWith)
+}
+
+// DifferenceWith removes the items of other from the set.
+func (set *HashSet) DifferenceWith(other *HashSet) {
+	set.update(other, (*Set[interface{}]).DifferenceWith)
+}
+
+// SymmetricDifferenceWith removes the items of other in the set, and adds the others.
+func (set *HashSet) SymmetricDifferenceWith(other *HashSet) {
+	set.update(other, (*Set[interface{}]).SymmetricDifferenceWith)
+}
+
+// update applies the in-place operation, which may replace the map of the items.
+func (set *HashSet) update(other *HashSet, op func(set, other *Set[interface{}])) {
+	s := set.set()
+	if set == other {
+		op(s, s)
+	} else {
+		op(s, other.set())
 	}
-	str += strings.Join(items, ", ")
-	return str
+	set.Items = s.items
+}
+
+// IsSubset checks whether all the items of the set are in other.
+func (set *HashSet) IsSubset(other *HashSet) bool {
+	return set.set().IsSubset(other.set())
+}
+
+// IsSuperset checks whether all the items of other are in the set.
+func (set *HashSet) IsSuperset(other *HashSet) bool {
+	return set.set().IsSuperset(other.set())
+}
+
+// IsDisjoint checks whether the sets have no item in common.
+func (set *HashSet) IsDisjoint(other *HashSet) bool {
+	return set.set().IsDisjoint(other.set())
+}
+
+// Equal checks whether the sets have the same items.
+func (set *HashSet) Equal(other *HashSet) bool {
+	return set.set().Equal(other.set())
 }
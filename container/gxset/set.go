@@ -0,0 +1,146 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxset
+
+import (
+	"fmt"
+	"iter"
+	"strings"
+)
+
+// Membership is the membership operations shared by the sets of this package and the filters of gxfilter.
+type Membership[T any] interface {
+	Add(items ...T)
+	Remove(items ...T)
+	Contains(items ...T) bool
+}
+
+// Interface is implemented by the sets of this package, HashSet is an Interface[interface{}].
+type Interface[T any] interface {
+	fmt.Stringer
+	Membership[T]
+	Empty() bool
+	Size() int
+	Clear()
+	Values() []T
+	Range(f func(item T) bool)
+	All() iter.Seq[T]
+}
+
+var (
+	_ Interface[interface{}] = (*HashSet)(nil)
+	_ Interface[int]         = (*Set[int])(nil)
+	_ Interface[int]         = (*ConcurrentSet[int])(nil)
+	_ Interface[int]         = (*TreeSet[int])(nil)
+)
+
+// Set is a hash set of the items of type T, the zero value is an empty set ready to use.
+// It is not safe for concurrent use.
+type Set[T comparable] struct {
+	items map[T]struct{}
+}
+
+// New returns a set of values.
+func New[T comparable](values ...T) *Set[T] {
+	set := &Set[T]{items: make(map[T]struct{}, len(values))}
+	set.Add(values...)
+	return set
+}
+
+// Add adds the items to the set.
+func (set *Set[T]) Add(items ...T) {
+	if set.items == nil {
+		set.items = make(map[T]struct{}, len(items))
+	}
+	for _, item := range items {
+		set.items[item] = itemExists
+	}
+}
+
+// Remove removes the items from the set.
+func (set *Set[T]) Remove(items ...T) {
+	for _, item := range items {
+		delete(set.items, item)
+	}
+}
+
+// Contains checks whether all the items are in the set, it is true if there is no item.
+func (set *Set[T]) Contains(items ...T) bool {
+	for _, item := range items {
+		if _, contains := set.items[item]; !contains {
+			return false
+		}
+	}
+	return true
+}
+
+// Empty checks whether the set has no item.
+func (set *Set[T]) Empty() bool {
+	return set.Size() == 0
+}
+
+// Size returns the number of the items.
+func (set *Set[T]) Size() int {
+	return len(set.items)
+}
+
+// Clear removes all the items.
+func (set *Set[T]) Clear() {
+	clear(set.items)
+}
+
+// Values returns the items in no particular order.
+func (set *Set[T]) Values() []T {
+	values := make([]T, 0, set.Size())
+	for item := range set.items {
+		values = append(values, item)
+	}
+	return values
+}
+
+// Range calls f for each item in no particular order until f returns false.
+// The set must not be modified by f except removing the current item.
+func (set *Set[T]) Range(f func(item T) bool) {
+	for item := range set.items {
+		if !f(item) {
+			return
+		}
+	}
+}
+
+// All returns an iterator over the items in no particular order, e.g.
+//
+//	for item := range set.All() {
+//	}
+func (set *Set[T]) All() iter.Seq[T] {
+	return set.Range
+}
+
+func (set *Set[T]) String() string {
+	return setString("Set", set.Range)
+}
+
+// setString dumps the items after the name of the set.
+func setString[T any](name string, rangeItems func(f func(item T) bool)) string {
+	var items []string
+	rangeItems(func(item T) bool {
+		items = append(items, fmt.Sprintf("%v", item))
+		return true
+	})
+	return name + "\n" + strings.Join(items, ", ")
+}
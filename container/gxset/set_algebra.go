@@ -0,0 +1,171 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxset
+
+// The set algebra comes in two variants: Union, Intersection, Difference and SymmetricDifference return
+// a new set and leave the operands unchanged, while UnionWith, IntersectWith, DifferenceWith and
+// SymmetricDifferenceWith update the receiver. Where the result allows, the smaller operand is iterated.
+// The operands can be the same set.
+
+// smaller returns a and b ordered by the size.
+func smaller[T comparable](a, b *Set[T]) (small, large *Set[T]) {
+	if a.Size() <= b.Size() {
+		return a, b
+	}
+	return b, a
+}
+
+// Clone returns a copy of the set.
+func (set *Set[T]) Clone() *Set[T] {
+	clone := &Set[T]{items: make(map[T]struct{}, set.Size())}
+	for item := range set.items {
+		clone.items[item] = itemExists
+	}
+	return clone
+}
+
+// Union returns the items in either set.
+func (set *Set[T]) Union(other *Set[T]) *Set[T] {
+	small, large := smaller(set, other)
+	union := large.Clone()
+	union.UnionWith(small)
+	return union
+}
+
+// Intersection returns the items in both sets.
+func (set *Set[T]) Intersection(other *Set[T]) *Set[T] {
+	small, large := smaller(set, other)
+	intersection := &Set[T]{items: make(map[T]struct{}, small.Size())}
+	for item := range small.items {
+		if _, ok := large.items[item]; ok {
+			intersection.items[item] = itemExists
+		}
+	}
+	return intersection
+}
+
+// Difference returns the items in the set but not in other.
+func (set *Set[T]) Difference(other *Set[T]) *Set[T] {
+	difference := &Set[T]{items: make(map[T]struct{}, set.Size())}
+	for item := range set.items {
+		if _, ok := other.items[item]; !ok {
+			difference.items[item] = itemExists
+		}
+	}
+	return difference
+}
+
+// SymmetricDifference returns the items in exactly one of the sets.
+func (set *Set[T]) SymmetricDifference(other *Set[T]) *Set[T] {
+	difference := set.Difference(other)
+	for item := range other.items {
+		if _, ok := set.items[item]; !ok {
+			difference.items[item] = itemExists
+		}
+	}
+	return difference
+}
+
+// UnionWith adds the items of other to the set.
+func (set *Set[T]) UnionWith(other *Set[T]) {
+	if set.items == nil {
+		set.items = make(map[T]struct{}, other.Size())
+	}
+	for item := range other.items {
+		set.items[item] = itemExists
+	}
+}
+
+// IntersectWith removes the items not in other from the set.
+func (set *Set[T]) IntersectWith(other *Set[T]) {
+	if set.Size() <= other.Size() {
+		for item := range set.items {
+			if _, ok := other.items[item]; !ok {
+				delete(set.items, item)
+			}
+		}
+		return
+	}
+	// the set is larger, keep the items of other in a new map.
+	set.items = set.Intersection(other).items
+}
+
+// DifferenceWith removes the items of other from the set.
+func (set *Set[T]) DifferenceWith(other *Set[T]) {
+	if other.Size() < set.Size() {
+		set.Remove(other.Values()...)
+		return
+	}
+	for item := range set.items {
+		if _, ok := other.items[item]; ok {
+			delete(set.items, item)
+		}
+	}
+}
+
+// SymmetricDifferenceWith removes the items of other in the set, and adds the others.
+func (set *Set[T]) SymmetricDifferenceWith(other *Set[T]) {
+	if set == other {
+		set.Clear()
+		return
+	}
+	if set.items == nil {
+		set.items = make(map[T]struct{}, other.Size())
+	}
+	for item := range other.items {
+		if _, ok := set.items[item]; ok {
+			delete(set.items, item)
+		} else {
+			set.items[item] = itemExists
+		}
+	}
+}
+
+// IsSubset checks whether all the items of the set are in other.
+func (set *Set[T]) IsSubset(other *Set[T]) bool {
+	if set.Size() > other.Size() {
+		return false
+	}
+	for item := range set.items {
+		if _, ok := other.items[item]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSuperset checks whether all the items of other are in the set.
+func (set *Set[T]) IsSuperset(other *Set[T]) bool {
+	return other.IsSubset(set)
+}
+
+// IsDisjoint checks whether the sets have no item in common.
+func (set *Set[T]) IsDisjoint(other *Set[T]) bool {
+	small, large := smaller(set, other)
+	for item := range small.items {
+		if _, ok := large.items[item]; ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Equal checks whether the sets have the same items.
+func (set *Set[T]) Equal(other *Set[T]) bool {
+	return set.Size() == other.Size() && set.IsSubset(other)
+}
@@ -0,0 +1,174 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxset
+
+import (
+	"testing"
+)
+
+func TestSetAlgebra(t *testing.T) {
+	tests := []struct {
+		a, b          []int
+		union         []int
+		intersection  []int
+		difference    []int
+		symmetricDiff []int
+	}{
+		{[]int{1, 2, 3}, []int{2, 3, 4, 5}, []int{1, 2, 3, 4, 5}, []int{2, 3}, []int{1}, []int{1, 4, 5}},
+		{[]int{1, 2, 3, 4, 5}, []int{2}, []int{1, 2, 3, 4, 5}, []int{2}, []int{1, 3, 4, 5}, []int{1, 3, 4, 5}},
+		{[]int{1, 2}, []int{3}, []int{1, 2, 3}, nil, []int{1, 2}, []int{1, 2, 3}},
+		{nil, []int{1}, []int{1}, nil, nil, []int{1}},
+		{[]int{1, 2}, []int{1, 2}, []int{1, 2}, []int{1, 2}, nil, nil},
+	}
+	for _, tt := range tests {
+		a, b := New(tt.a...), New(tt.b...)
+		check := func(op string, actual *Set[int], expected []int) {
+			if !actual.Equal(New(expected...)) {
+				t.Errorf("%v %s %v: Got %v expected %v", tt.a, op, tt.b, actual.Values(), expected)
+			}
+		}
+		check("union", a.Union(b), tt.union)
+		check("intersection", a.Intersection(b), tt.intersection)
+		check("difference", a.Difference(b), tt.difference)
+		check("symmetric difference", a.SymmetricDifference(b), tt.symmetricDiff)
+		check("a", a, tt.a)
+		check("b", b, tt.b)
+
+		for _, inPlace := range []struct {
+			op       string
+			f        func(set, other *Set[int])
+			expected []int
+		}{
+			{"union with", (*Set[int]).UnionWith, tt.union},
+			{"intersect with", (*Set[int]).IntersectWith, tt.intersection},
+			{"difference with", (*Set[int]).DifferenceWith, tt.difference},
+			{"symmetric difference with", (*Set[int]).SymmetricDifferenceWith, tt.symmetricDiff},
+		} {
+			set := New(tt.a...)
+			inPlace.f(set, b)
+			check(inPlace.op, set, inPlace.expected)
+		}
+	}
+}
+
+func TestSetAlgebraSameSet(t *testing.T) {
+	set := New(1, 2, 3)
+	if actualValue := set.Union(set).Size(); actualValue != 3 {
+		t.Errorf("Got %v expected %v", actualValue, 3)
+	}
+	set.IntersectWith(set)
+	if actualValue := set.Size(); actualValue != 3 {
+		t.Errorf("Got %v expected %v", actualValue, 3)
+	}
+	set.SymmetricDifferenceWith(set)
+	if actualValue := set.Empty(); actualValue != true {
+		t.Errorf("Got %v expected %v", actualValue, true)
+	}
+	set.Add(1, 2)
+	set.DifferenceWith(set)
+	if actualValue := set.Empty(); actualValue != true {
+		t.Errorf("Got %v expected %v", actualValue, true)
+	}
+}
+
+func TestSetRelations(t *testing.T) {
+	tests := []struct {
+		a, b     []int
+		subset   bool
+		superset bool
+		disjoint bool
+		equal    bool
+	}{
+		{[]int{1, 2}, []int{1, 2, 3}, true, false, false, false},
+		{[]int{1, 2, 3}, []int{1, 2}, false, true, false, false},
+		{[]int{1, 2}, []int{2, 1}, true, true, false, true},
+		{[]int{1, 2}, []int{3, 4}, false, false, true, false},
+		{[]int{1, 4}, []int{1, 2, 3}, false, false, false, false},
+		{nil, []int{1}, true, false, true, false},
+		{nil, nil, true, true, true, true},
+	}
+	for _, tt := range tests {
+		a, b := New(tt.a...), New(tt.b...)
+		if actualValue := a.IsSubset(b); actualValue != tt.subset {
+			t.Errorf("%v subset %v: Got %v expected %v", tt.a, tt.b, actualValue, tt.subset)
+		}
+		if actualValue := a.IsSuperset(b); actualValue != tt.superset {
+			t.Errorf("%v superset %v: Got %v expected %v", tt.a, tt.b, actualValue, tt.superset)
+		}
+		if actualValue := a.IsDisjoint(b); actualValue != tt.disjoint {
+			t.Errorf("%v disjoint %v: Got %v expected %v", tt.a, tt.b, actualValue, tt.disjoint)
+		}
+		if actualValue := a.Equal(b); actualValue != tt.equal {
+			t.Errorf("%v equal %v: Got %v expected %v", tt.a, tt.b, actualValue, tt.equal)
+		}
+	}
+}
+
+func TestHashSetAlgebra(t *testing.T) {
+	a, b := NewSet(1, "a", 2.5), NewSet("a", "b")
+	if actualValue := a.Union(b); !actualValue.Equal(NewSet(1, "a", "b", 2.5)) {
+		t.Errorf("Got %v expected %v", actualValue, NewSet(1, "a", "b", 2.5))
+	}
+	if actualValue := a.Intersection(b); !actualValue.Equal(NewSet("a")) {
+		t.Errorf("Got %v expected %v", actualValue, NewSet("a"))
+	}
+	if actualValue := a.SymmetricDifference(b); !actualValue.Equal(NewSet(1, "b", 2.5)) {
+		t.Errorf("Got %v expected %v", actualValue, NewSet(1, "b", 2.5))
+	}
+	if actualValue := a.IsDisjoint(b); actualValue != false {
+		t.Errorf("Got %v expected %v", actualValue, false)
+	}
+
+	// the larger set intersected in place gets a new map of the items.
+	a.IntersectWith(b)
+	if actualValue := a.Equal(NewSet("a")); actualValue != true {
+		t.Errorf("Got %v expected %v", actualValue, true)
+	}
+	if actualValue := a.IsSubset(b); actualValue != true {
+		t.Errorf("Got %v expected %v", actualValue, true)
+	}
+	a.DifferenceWith(b)
+	if actualValue := a.Empty(); actualValue != true {
+		t.Errorf("Got %v expected %v", actualValue, true)
+	}
+
+	var zero HashSet
+	zero.UnionWith(b)
+	zero.SymmetricDifferenceWith(&zero)
+	if actualValue := zero.Empty(); actualValue != true {
+		t.Errorf("Got %v expected %v", actualValue, true)
+	}
+	zero.SymmetricDifferenceWith(b)
+	if actualValue := zero.Equal(b); actualValue != true {
+		t.Errorf("Got %v expected %v", actualValue, true)
+	}
+}
+
+func BenchmarkSetIntersection(b *testing.B) {
+	small, large := New[int](), New[int]()
+	for n := 0; n < 10000; n++ {
+		large.Add(n)
+		if n%100 == 0 {
+			small.Add(n)
+		}
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		large.Intersection(small)
+	}
+}
@@ -0,0 +1,139 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxset
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+)
+
+import (
+	"github.com/pkg/errors"
+)
+
+// The sets marshal JSON as arrays of the items, in ascending order for TreeSet and in no particular
+// order for the others. Unmarshaling replaces the items of the set, and like the other types,
+// null leaves the set unchanged.
+
+var nullJSON = []byte("null")
+
+// JavaClassName returns the java class name for hessian.
+func (HashSet) JavaClassName() string {
+	return "java.util.HashSet"
+}
+
+// Get returns the items for hessian, which encodes HashSet as a java.util.HashSet once it is registered by
+//
+//	hessian.SetCollectionSerialize(&gxset.HashSet{})
+//
+// Then a java.util.Set returned by a java provider is decoded into a HashSet as well.
+func (set *HashSet) Get() []interface{} {
+	return set.Values()
+}
+
+// Set replaces the items by the ones decoded by hessian.
+func (set *HashSet) Set(values []interface{}) {
+	set.Items = make(map[interface{}]struct{}, len(values))
+	set.Add(values...)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (set HashSet) MarshalJSON() ([]byte, error) {
+	return json.Marshal(set.Values())
+}
+
+// UnmarshalJSON implements json.Unmarshaler. As the items are decoded into interface{}, the numbers
+// are float64, and an array or object item is an error as it is unhashable. Use Set[T] for the typed items.
+func (set *HashSet) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, nullJSON) {
+		return nil
+	}
+	var values []interface{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	for _, value := range values {
+		if value != nil && !reflect.TypeOf(value).Comparable() {
+			return errors.Errorf("gxset: can't unmarshal the unhashable item %v into HashSet", value)
+		}
+	}
+	set.Set(values)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (set Set[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(set.Values())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (set *Set[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, nullJSON) {
+		return nil
+	}
+	var values []T
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	set.items = make(map[T]struct{}, len(values))
+	set.Add(values...)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (set *ConcurrentSet[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(set.Values())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (set *ConcurrentSet[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, nullJSON) {
+		return nil
+	}
+	var values []T
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	set.Clear()
+	set.Add(values...)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (set TreeSet[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(set.Values())
+}
+
+// UnmarshalJSON implements json.Unmarshaler. The set must be created by NewTreeSet or NewTreeSetFunc
+// to unmarshal the items in its order.
+func (set *TreeSet[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, nullJSON) {
+		return nil
+	}
+	if set.compare == nil {
+		return errors.New("gxset: can't unmarshal into TreeSet without a comparison function")
+	}
+	var values []T
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	set.Clear()
+	set.Add(values...)
+	return nil
+}
@@ -0,0 +1,136 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxset
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestHashSetJSON(t *testing.T) {
+	data, err := json.Marshal(NewSet("a"))
+	if err != nil || string(data) != `["a"]` {
+		t.Errorf("Got %s %v expected %v", data, err, `["a"]`)
+	}
+
+	var set HashSet
+	if err := json.Unmarshal([]byte(`["a", 1, null, "a"]`), &set); err != nil {
+		t.Fatalf("Got %v expected %v", err, nil)
+	}
+	if actualValue := set.Equal(NewSet("a", 1.0, nil)); actualValue != true {
+		t.Errorf("Got %v expected %v", set.Values(), []interface{}{"a", 1.0, nil})
+	}
+	if err := json.Unmarshal([]byte(`["a", ["b"]]`), &set); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+	if err := json.Unmarshal([]byte(`{"a": 1}`), &set); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+
+	// a set in a struct marshals as an array.
+	type provider struct {
+		URLs HashSet `json:"urls"`
+	}
+	data, err = json.Marshal(provider{URLs: *NewSet("dubbo://1")})
+	if err != nil || string(data) != `{"urls":["dubbo://1"]}` {
+		t.Errorf("Got %s %v expected %v", data, err, `{"urls":["dubbo://1"]}`)
+	}
+}
+
+func TestGenericSetJSON(t *testing.T) {
+	set := New(1, 2)
+	set.Add(3)
+	data, err := json.Marshal(NewTreeSet(3, 1, 2))
+	if err != nil || string(data) != `[1,2,3]` {
+		t.Errorf("Got %s %v expected %v", data, err, `[1,2,3]`)
+	}
+
+	var fromJSON Set[int]
+	if err := json.Unmarshal([]byte(`[1, 2, 3, 3]`), &fromJSON); err != nil || !fromJSON.Equal(set) {
+		t.Errorf("Got %v %v expected %v", fromJSON.Values(), err, set.Values())
+	}
+	if err := json.Unmarshal([]byte(`["1"]`), &fromJSON); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+
+	var concurrent ConcurrentSet[int]
+	concurrent.Add(4)
+	if err := json.Unmarshal([]byte(`[1, 2]`), &concurrent); err != nil || concurrent.Size() != 2 || concurrent.Contains(4) {
+		t.Errorf("Got %v %v expected %v", concurrent.Values(), err, []int{1, 2})
+	}
+	data, err = json.Marshal(&concurrent)
+	if err != nil || len(data) != len(`[1,2]`) {
+		t.Errorf("Got %s %v expected %v", data, err, `[1,2]`)
+	}
+
+	tree := NewTreeSet[int]()
+	if err := json.Unmarshal([]byte(`[3, 1]`), tree); err != nil || tree.String() != "TreeSet\n1, 3" {
+		t.Errorf("Got %v %v expected %v", tree, err, "TreeSet\n1, 3")
+	}
+	var zero TreeSet[int]
+	if err := json.Unmarshal([]byte(`[1]`), &zero); err == nil {
+		t.Errorf("Got %v expected an error", err)
+	}
+}
+
+func TestSetJSONNull(t *testing.T) {
+	hashSet := NewSet("a")
+	if err := json.Unmarshal([]byte(`null`), hashSet); err != nil || !hashSet.Equal(NewSet("a")) {
+		t.Errorf("Got %v %v expected %v", hashSet.Values(), err, []interface{}{"a"})
+	}
+	set := New(1, 2)
+	if err := json.Unmarshal([]byte(`null`), set); err != nil || !set.Equal(New(1, 2)) {
+		t.Errorf("Got %v %v expected %v", set.Values(), err, []int{1, 2})
+	}
+	var concurrent ConcurrentSet[int]
+	concurrent.Add(1)
+	if err := json.Unmarshal([]byte(`null`), &concurrent); err != nil || concurrent.Size() != 1 {
+		t.Errorf("Got %v %v expected %v", concurrent.Values(), err, []int{1})
+	}
+	var zero TreeSet[int]
+	if err := json.Unmarshal([]byte(`null`), &zero); err != nil {
+		t.Errorf("Got %v expected %v", err, nil)
+	}
+
+	// a null field keeps the set of the struct.
+	var values struct {
+		URLs Set[string] `json:"urls"`
+	}
+	values.URLs.Add("dubbo://1")
+	if err := json.Unmarshal([]byte(`{"urls": null}`), &values); err != nil || !values.URLs.Contains("dubbo://1") {
+		t.Errorf("Got %v %v expected %v", values.URLs.Values(), err, []string{"dubbo://1"})
+	}
+}
+
+// hessianCollection is the interface of the java collections encoded by hessian.
+type hessianCollection interface {
+	Get() []interface{}
+	Set([]interface{})
+	JavaClassName() string
+}
+
+func TestHashSetHessian(t *testing.T) {
+	var collection hessianCollection = &HashSet{}
+	collection.Set([]interface{}{"a", "b", "a"})
+	if actualValue := len(collection.Get()); actualValue != 2 {
+		t.Errorf("Got %v expected %v", actualValue, 2)
+	}
+	if actualValue := collection.JavaClassName(); actualValue != "java.util.HashSet" {
+		t.Errorf("Got %v expected %v", actualValue, "java.util.HashSet")
+	}
+}
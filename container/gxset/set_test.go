@@ -0,0 +1,137 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxset
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestGenericSet(t *testing.T) {
+	set := New("b", "a")
+	set.Add("c", "a")
+	if actualValue := set.Size(); actualValue != 3 {
+		t.Errorf("Got %v expected %v", actualValue, 3)
+	}
+	if actualValue := set.Contains("a", "b", "c"); actualValue != true {
+		t.Errorf("Got %v expected %v", actualValue, true)
+	}
+	if actualValue := set.Contains("a", "d"); actualValue != false {
+		t.Errorf("Got %v expected %v", actualValue, false)
+	}
+
+	values := set.Values()
+	sort.Strings(values)
+	if actualValue := len(values); actualValue != 3 || values[0] != "a" || values[2] != "c" {
+		t.Errorf("Got %v expected %v", values, []string{"a", "b", "c"})
+	}
+
+	set.Remove("b", "d")
+	if actualValue := set.Contains("b"); actualValue != false {
+		t.Errorf("Got %v expected %v", actualValue, false)
+	}
+	set.Clear()
+	if actualValue := set.Empty(); actualValue != true {
+		t.Errorf("Got %v expected %v", actualValue, true)
+	}
+
+	// the zero value is ready to use.
+	var ints Set[int]
+	if actualValue := ints.Contains(1); actualValue != false {
+		t.Errorf("Got %v expected %v", actualValue, false)
+	}
+	ints.Add(1)
+	if actualValue := ints.String(); actualValue != "Set\n1" {
+		t.Errorf("Got %v expected %v", actualValue, "Set\n1")
+	}
+}
+
+func TestSetIteration(t *testing.T) {
+	set := New(1, 2, 3, 4)
+	sum := 0
+	set.Range(func(item int) bool {
+		sum += item
+		return true
+	})
+	if sum != 10 {
+		t.Errorf("Got %v expected %v", sum, 10)
+	}
+
+	count := 0
+	set.Range(func(item int) bool {
+		count++
+		return count < 2
+	})
+	if count != 2 {
+		t.Errorf("Got %v expected %v", count, 2)
+	}
+
+	sum = 0
+	for item := range set.All() {
+		if item == 3 {
+			set.Remove(item)
+			continue
+		}
+		sum += item
+	}
+	if sum != 7 || set.Size() != 3 {
+		t.Errorf("Got %v and %v expected %v and %v", sum, set.Size(), 7, 3)
+	}
+	for range set.All() {
+		break
+	}
+}
+
+func TestHashSetWrapper(t *testing.T) {
+	set := NewSet(1, "a")
+	set.Add(2.5)
+	sum := 0
+	for item := range set.All() {
+		if i, ok := item.(int); ok {
+			sum += i
+		}
+	}
+	if sum != 1 || set.Size() != 3 {
+		t.Errorf("Got %v and %v expected %v and %v", sum, set.Size(), 1, 3)
+	}
+
+	// the zero value allocates the items on the first Add.
+	var zero HashSet
+	zero.Add("x")
+	if actualValue := zero.Contains("x"); actualValue != true {
+		t.Errorf("Got %v expected %v", actualValue, true)
+	}
+	if actualValue := zero.String(); actualValue != "HashSet\nx" {
+		t.Errorf("Got %v expected %v", actualValue, "HashSet\nx")
+	}
+}
+
+func BenchmarkSetContains1000(b *testing.B) {
+	b.StopTimer()
+	size := 1000
+	set := New[int]()
+	for n := 0; n < size; n++ {
+		set.Add(n)
+	}
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		for n := 0; n < size; n++ {
+			set.Contains(n)
+		}
+	}
+}
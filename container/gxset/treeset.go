@@ -0,0 +1,320 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxset
+
+import (
+	"cmp"
+	"iter"
+)
+
+// TreeSet is a set of the items sorted by a comparison function, it is backed by an AVL tree, so Add,
+// Remove, Contains, Floor and Ceiling take O(log n). It is not safe for concurrent use.
+// Create it by NewTreeSet or NewTreeSetFunc, the zero value has no comparison function.
+type TreeSet[T any] struct {
+	root    *treeNode[T]
+	size    int
+	compare func(a, b T) int
+}
+
+type treeNode[T any] struct {
+	item        T
+	left, right *treeNode[T]
+	height      int
+}
+
+// NewTreeSet returns a tree set of values in the natural order.
+func NewTreeSet[T cmp.Ordered](values ...T) *TreeSet[T] {
+	return NewTreeSetFunc(cmp.Compare[T], values...)
+}
+
+// NewTreeSetFunc returns a tree set of values in the order of compare, which returns a negative number
+// if a < b, a positive number if a > b and zero if a and b are the same item.
+func NewTreeSetFunc[T any](compare func(a, b T) int, values ...T) *TreeSet[T] {
+	set := &TreeSet[T]{compare: compare}
+	set.Add(values...)
+	return set
+}
+
+// Add adds the items to the set.
+func (set *TreeSet[T]) Add(items ...T) {
+	for _, item := range items {
+		var added bool
+		set.root, added = set.insert(set.root, item)
+		if added {
+			set.size++
+		}
+	}
+}
+
+// Remove removes the items from the set.
+func (set *TreeSet[T]) Remove(items ...T) {
+	for _, item := range items {
+		var removed bool
+		set.root, removed = set.delete(set.root, item)
+		if removed {
+			set.size--
+		}
+	}
+}
+
+// Contains checks whether all the items are in the set, it is true if there is no item.
+func (set *TreeSet[T]) Contains(items ...T) bool {
+	for _, item := range items {
+		if set.find(item) == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Empty checks whether the set has no item.
+func (set *TreeSet[T]) Empty() bool {
+	return set.size == 0
+}
+
+// Size returns the number of the items.
+func (set *TreeSet[T]) Size() int {
+	return set.size
+}
+
+// Clear removes all the items.
+func (set *TreeSet[T]) Clear() {
+	set.root = nil
+	set.size = 0
+}
+
+// Values returns the items in ascending order.
+func (set *TreeSet[T]) Values() []T {
+	values := make([]T, 0, set.size)
+	set.Range(func(item T) bool {
+		values = append(values, item)
+		return true
+	})
+	return values
+}
+
+// Range calls f for each item in ascending order until f returns false.
+// The set must not be modified by f.
+func (set *TreeSet[T]) Range(f func(item T) bool) {
+	set.rangeNode(set.root, f)
+}
+
+// All returns an iterator over the items in ascending order.
+func (set *TreeSet[T]) All() iter.Seq[T] {
+	return set.Range
+}
+
+// RangeBetween calls f for each item in [lo, hi) in ascending order until f returns false.
+// The set must not be modified by f.
+func (set *TreeSet[T]) RangeBetween(lo, hi T, f func(item T) bool) {
+	set.rangeBetween(set.root, lo, hi, f)
+}
+
+// Between returns an iterator over the items in [lo, hi) in ascending order.
+func (set *TreeSet[T]) Between(lo, hi T) iter.Seq[T] {
+	return func(f func(item T) bool) {
+		set.RangeBetween(lo, hi, f)
+	}
+}
+
+// Floor returns the greatest item less than or equal to item, ok is false if there is no such item.
+func (set *TreeSet[T]) Floor(item T) (floor T, ok bool) {
+	for node := set.root; node != nil; {
+		if c := set.compare(node.item, item); c > 0 {
+			node = node.left
+		} else {
+			floor, ok = node.item, true
+			if c == 0 {
+				break
+			}
+			node = node.right
+		}
+	}
+	return floor, ok
+}
+
+// Ceiling returns the least item greater than or equal to item, ok is false if there is no such item.
+func (set *TreeSet[T]) Ceiling(item T) (ceiling T, ok bool) {
+	for node := set.root; node != nil; {
+		if c := set.compare(node.item, item); c < 0 {
+			node = node.right
+		} else {
+			ceiling, ok = node.item, true
+			if c == 0 {
+				break
+			}
+			node = node.left
+		}
+	}
+	return ceiling, ok
+}
+
+// First returns the least item, ok is false if the set is empty.
+func (set *TreeSet[T]) First() (first T, ok bool) {
+	if set.root == nil {
+		return first, false
+	}
+	return set.root.min().item, true
+}
+
+// Last returns the greatest item, ok is false if the set is empty.
+func (set *TreeSet[T]) Last() (last T, ok bool) {
+	node := set.root
+	if node == nil {
+		return last, false
+	}
+	for node.right != nil {
+		node = node.right
+	}
+	return node.item, true
+}
+
+func (set *TreeSet[T]) String() string {
+	return setString("TreeSet", set.Range)
+}
+
+func (set *TreeSet[T]) find(item T) *treeNode[T] {
+	for node := set.root; node != nil; {
+		switch c := set.compare(item, node.item); {
+		case c < 0:
+			node = node.left
+		case c > 0:
+			node = node.right
+		default:
+			return node
+		}
+	}
+	return nil
+}
+
+func (set *TreeSet[T]) rangeNode(node *treeNode[T], f func(item T) bool) bool {
+	if node == nil {
+		return true
+	}
+	return set.rangeNode(node.left, f) && f(node.item) && set.rangeNode(node.right, f)
+}
+
+func (set *TreeSet[T]) rangeBetween(node *treeNode[T], lo, hi T, f func(item T) bool) bool {
+	if node == nil {
+		return true
+	}
+	// the left subtree has the items less than node, the right one the greater ones.
+	cmpLo, beforeHi := set.compare(node.item, lo), set.compare(node.item, hi) < 0
+	if cmpLo > 0 && !set.rangeBetween(node.left, lo, hi, f) {
+		return false
+	}
+	if cmpLo >= 0 && beforeHi && !f(node.item) {
+		return false
+	}
+	return !beforeHi || set.rangeBetween(node.right, lo, hi, f)
+}
+
+// insert adds item into the subtree of node, and returns the balanced subtree.
+func (set *TreeSet[T]) insert(node *treeNode[T], item T) (*treeNode[T], bool) {
+	if node == nil {
+		return &treeNode[T]{item: item, height: 1}, true
+	}
+	var added bool
+	switch c := set.compare(item, node.item); {
+	case c < 0:
+		node.left, added = set.insert(node.left, item)
+	case c > 0:
+		node.right, added = set.insert(node.right, item)
+	default:
+		return node, false
+	}
+	return node.balance(), added
+}
+
+// delete removes item from the subtree of node, and returns the balanced subtree.
+func (set *TreeSet[T]) delete(node *treeNode[T], item T) (*treeNode[T], bool) {
+	if node == nil {
+		return nil, false
+	}
+	var removed bool
+	switch c := set.compare(item, node.item); {
+	case c < 0:
+		node.left, removed = set.delete(node.left, item)
+	case c > 0:
+		node.right, removed = set.delete(node.right, item)
+	default:
+		if node.left == nil {
+			return node.right, true
+		}
+		if node.right == nil {
+			return node.left, true
+		}
+		// replace the item by its successor, which is removed from the right subtree.
+		node.item = node.right.min().item
+		node.right, _ = set.delete(node.right, node.item)
+		removed = true
+	}
+	return node.balance(), removed
+}
+
+func (node *treeNode[T]) min() *treeNode[T] {
+	for node.left != nil {
+		node = node.left
+	}
+	return node
+}
+
+func (node *treeNode[T]) getHeight() int {
+	if node == nil {
+		return 0
+	}
+	return node.height
+}
+
+func (node *treeNode[T]) updateHeight() {
+	node.height = 1 + max(node.left.getHeight(), node.right.getHeight())
+}
+
+// balance rotates node if its subtrees differ in height by 2, and returns the root of the subtree.
+func (node *treeNode[T]) balance() *treeNode[T] {
+	node.updateHeight()
+	switch factor := node.left.getHeight() - node.right.getHeight(); {
+	case factor > 1:
+		if node.left.left.getHeight() < node.left.right.getHeight() {
+			node.left = node.left.rotateLeft()
+		}
+		return node.rotateRight()
+	case factor < -1:
+		if node.right.right.getHeight() < node.right.left.getHeight() {
+			node.right = node.right.rotateRight()
+		}
+		return node.rotateLeft()
+	}
+	return node
+}
+
+func (node *treeNode[T]) rotateLeft() *treeNode[T] {
+	right := node.right
+	node.right, right.left = right.left, node
+	node.updateHeight()
+	right.updateHeight()
+	return right
+}
+
+func (node *treeNode[T]) rotateRight() *treeNode[T] {
+	left := node.left
+	node.left, left.right = left.right, node
+	node.updateHeight()
+	left.updateHeight()
+	return left
+}
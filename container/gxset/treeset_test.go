@@ -0,0 +1,154 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxset
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestTreeSet(t *testing.T) {
+	set := NewTreeSet(5, 1, 9, 3, 7, 3)
+	if actualValue := set.Size(); actualValue != 5 {
+		t.Errorf("Got %v expected %v", actualValue, 5)
+	}
+	if actualValue := set.String(); actualValue != "TreeSet\n1, 3, 5, 7, 9" {
+		t.Errorf("Got %v expected %v", actualValue, "TreeSet\n1, 3, 5, 7, 9")
+	}
+
+	tests := []struct {
+		item           int
+		floor, ceiling int
+		floorOk        bool
+		ceilingOk      bool
+	}{
+		{0, 0, 1, false, true},
+		{1, 1, 1, true, true},
+		{4, 3, 5, true, true},
+		{9, 9, 9, true, true},
+		{10, 9, 0, true, false},
+	}
+	for _, tt := range tests {
+		if floor, ok := set.Floor(tt.item); floor != tt.floor || ok != tt.floorOk {
+			t.Errorf("floor %v: Got %v %v expected %v %v", tt.item, floor, ok, tt.floor, tt.floorOk)
+		}
+		if ceiling, ok := set.Ceiling(tt.item); ceiling != tt.ceiling || ok != tt.ceilingOk {
+			t.Errorf("ceiling %v: Got %v %v expected %v %v", tt.item, ceiling, ok, tt.ceiling, tt.ceilingOk)
+		}
+	}
+
+	var between []int
+	for item := range set.Between(3, 9) {
+		between = append(between, item)
+	}
+	if len(between) != 3 || between[0] != 3 || between[2] != 7 {
+		t.Errorf("Got %v expected %v", between, []int{3, 5, 7})
+	}
+	if first, _ := set.First(); first != 1 {
+		t.Errorf("Got %v expected %v", first, 1)
+	}
+	if last, _ := set.Last(); last != 9 {
+		t.Errorf("Got %v expected %v", last, 9)
+	}
+
+	set.Remove(5, 6)
+	if actualValue := set.Contains(5); actualValue != false || set.Size() != 4 {
+		t.Errorf("Got %v expected %v", actualValue, false)
+	}
+	set.Clear()
+	if _, ok := set.First(); ok || !set.Empty() {
+		t.Errorf("Got %v expected %v", ok, false)
+	}
+
+	// the order of the comparison function.
+	words := NewTreeSetFunc(func(a, b string) int {
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	}, "b", "A", "a", "C")
+	if actualValue := words.Values(); len(actualValue) != 3 || actualValue[0] != "A" {
+		t.Errorf("Got %v expected %v", actualValue, []string{"A", "b", "C"})
+	}
+}
+
+func TestTreeSetRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	set := NewTreeSet[int]()
+	items := map[int]struct{}{}
+	for i := 0; i < 20000; i++ {
+		item := r.Intn(1000)
+		if r.Intn(3) == 0 {
+			set.Remove(item)
+			delete(items, item)
+		} else {
+			set.Add(item)
+			items[item] = itemExists
+		}
+	}
+	expected := make([]int, 0, len(items))
+	for item := range items {
+		expected = append(expected, item)
+	}
+	sort.Ints(expected)
+	values := set.Values()
+	if len(values) != len(expected) || set.Size() != len(expected) {
+		t.Fatalf("Got %v expected %v", len(values), len(expected))
+	}
+	for i := range values {
+		if values[i] != expected[i] {
+			t.Fatalf("Got %v expected %v", values[i], expected[i])
+		}
+	}
+	checkBalanced(t, set.root)
+
+	for i := 0; i < 1000; i++ {
+		lo, hi := r.Intn(1100)-50, r.Intn(1100)-50
+		j := sort.SearchInts(expected, lo)
+		set.RangeBetween(lo, hi, func(item int) bool {
+			if j >= len(expected) || item != expected[j] {
+				t.Fatalf("[%v, %v): Got %v", lo, hi, item)
+			}
+			j++
+			return true
+		})
+		if j < len(expected) && expected[j] < hi {
+			t.Fatalf("[%v, %v): missing %v", lo, hi, expected[j])
+		}
+	}
+}
+
+// checkBalanced checks the heights of the AVL tree.
+func checkBalanced(t *testing.T, node *treeNode[int]) int {
+	if node == nil {
+		return 0
+	}
+	left, right := checkBalanced(t, node.left), checkBalanced(t, node.right)
+	if left-right > 1 || right-left > 1 || node.height != 1+max(left, right) {
+		t.Fatalf("unbalanced at %v: %v, %v, %v", node.item, left, right, node.height)
+	}
+	return node.height
+}
+
+func BenchmarkTreeSetAdd1000(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		set := NewTreeSet[int]()
+		for n := 0; n < 1000; n++ {
+			set.Add(n)
+		}
+	}
+}
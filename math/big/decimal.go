@@ -19,6 +19,7 @@ package gxbig
 
 import (
 	"math"
+	"math/big"
 	"strconv"
 )
 
@@ -31,6 +32,8 @@ var (
 	ErrOverflow  = errors.Errorf("Data Overflow")
 	ErrTruncated = errors.Errorf("Data Truncated")
 	ErrDivByZero = errors.Errorf("Division by 0")
+	// ErrRoundingNecessary is returned by ModeUnnecessary rounding when digits would be discarded.
+	ErrRoundingNecessary = errors.Errorf("Rounding Necessary")
 )
 
 // RoundMode is the type for round mode.
@@ -59,13 +62,32 @@ const (
 
 	DivFracIncr = 4
 
-	// ModeHalfEven rounds normally.
+	// The round modes follow java.math.RoundingMode.
+
+	// ModeCeiling rounds towards positive infinity.
+	ModeCeiling RoundMode = 0
+	// ModeUp rounds away from zero.
+	ModeUp RoundMode = 1
+	// ModeFloor rounds towards negative infinity.
+	ModeFloor RoundMode = 2
+	// ModeHalfEven rounds normally like TiDB, towards the nearest neighbor, or away from zero if both are
+	// equidistant, e.g. 2.5 is 3. Despite its name it is the same as ModeHalfUp, use ModeHalfToEven for
+	// the banker's rounding of java.math.RoundingMode.HALF_EVEN.
 	ModeHalfEven RoundMode = 5
-	// Truncate just truncates the decimal.
+	// ModeHalfUp rounds towards the nearest neighbor, or away from zero if both are equidistant.
+	ModeHalfUp RoundMode = 6
+	// ModeHalfDown rounds towards the nearest neighbor, or towards zero if both are equidistant.
+	ModeHalfDown RoundMode = 7
+	// ModeHalfToEven rounds towards the nearest neighbor, or towards the even neighbor if both are equidistant.
+	ModeHalfToEven RoundMode = 8
+	// ModeTruncate just truncates the decimal.
 	ModeTruncate RoundMode = 10
-	// Ceiling is not supported now.
-	modeCeiling     RoundMode = 0
-	maxDecimalScale           = 30
+	// ModeDown rounds towards zero, it is the same as ModeTruncate.
+	ModeDown = ModeTruncate
+	// ModeUnnecessary asserts that the result is exact, Round returns ErrRoundingNecessary otherwise.
+	ModeUnnecessary RoundMode = 11
+
+	maxDecimalScale = 30
 )
 
 var (
@@ -190,10 +212,10 @@ func fixWordCntError(wordsInt, wordsFrac int) (newWordsInt int, newWordsFrac int
 }
 
 /*
-  countLeadingZeroes returns the number of leading zeroes that can be removed from fraction.
+countLeadingZeroes returns the number of leading zeroes that can be removed from fraction.
 
-  @param   i    start index
-  @param   word value to compare against list of powers of 10
+@param   i    start index
+@param   word value to compare against list of powers of 10
 */
 func countLeadingZeroes(i int, word int32) int {
 	leading := 0
@@ -205,10 +227,10 @@ func countLeadingZeroes(i int, word int32) int {
 }
 
 /*
-  countTrailingZeros returns the number of trailing zeroes that can be removed from fraction.
+countTrailingZeros returns the number of trailing zeroes that can be removed from fraction.
 
-  @param   i    start index
-  @param   word  value to compare against list of powers of 10
+@param   i    start index
+@param   word  value to compare against list of powers of 10
 */
 func countTrailingZeroes(i int, word int32) int {
 	trailing := 0
@@ -243,14 +265,40 @@ type Decimal struct {
 	// A word is an int32 value can hold 9 digits.(0 <= word < wordBase)
 	wordBuf [maxWordBufLen]int32
 
-	// for hessian
-	Value string
+	// JavaValue is the printable representation of the decimal, it is the only field
+	// java.math.BigDecimal is serialized with by hessian, under the name "value" of the tag.
+	// It is kept consistent with the numeric state by every mutating operation.
+	// It was named Value, which is the method of driver.Valuer now.
+	JavaValue string `hessian:"value"`
 }
 
+// JavaClassName returns the java class name for hessian.
 func (Decimal) JavaClassName() string {
 	return "java.math.BigDecimal"
 }
 
+// updateJavaValue makes JavaValue consistent with the numeric state.
+func (d *Decimal) updateJavaValue() {
+	d.JavaValue = string(d.ToBytes())
+}
+
+// HessianValue returns JavaValue, the field "value" that java.math.BigDecimal is encoded with by hessian.
+// Java reads it back with `new BigDecimal(value)`, which restores the same unscaled value and scale.
+// The operations keep JavaValue consistent, so a serializer can read the field by reflection, HessianValue
+// also fills it for a zero Decimal{} that no operation has set, which is encoded as "0".
+func (d *Decimal) HessianValue() string {
+	d.updateJavaValue()
+	return d.JavaValue
+}
+
+// SetHessianValue restores the numeric state from the field "value" of java.math.BigDecimal,
+// e.g. "1.23", "-0.001" or "1.23E+10" as java writes it.
+// A deserializer that sets only the field JavaValue by reflection must call it with JavaValue after decoding.
+func (d *Decimal) SetHessianValue(value string) error {
+	*d = zeroBigDecimal
+	return d.FromString(value)
+}
+
 // IsNegative returns whether a decimal is negative.
 func (d *Decimal) IsNegative() bool {
 	return d.negative
@@ -309,11 +357,10 @@ func (d *Decimal) removeTrailingZeros() (lastWordIdx int, digitsFrac int) {
 
 // ToString converts decimal to its printable string representation without rounding.
 //
-//  RETURN VALUE
-//
-//      str       - result string
-//      errCode   - eDecOK/eDecTruncate/eDecOverflow
+//	RETURN VALUE
 //
+//	    str       - result string
+//	    errCode   - eDecOK/eDecTruncate/eDecOverflow
 func (d *Decimal) ToBytes() (str []byte) {
 	str = make([]byte, d.stringSize())
 	digitsFrac := int(d.digitsFrac)
@@ -391,6 +438,90 @@ func (d *Decimal) ToBytes() (str []byte) {
 	return
 }
 
+// ToPlainString returns the decimal without an exponent field, the same as java.math.BigDecimal.toPlainString().
+// As the scale of a Decimal is never negative, it is the same as ToBytes.
+func (d *Decimal) ToPlainString() string {
+	return string(d.ToBytes())
+}
+
+// ToScientificString returns the decimal the same as java.math.BigDecimal.toString(),
+// using scientific notation if the exponent is less than -6, e.g. 1E-7 or 1.23E-10.
+func (d *Decimal) ToScientificString() string {
+	return d.toJavaString(false)
+}
+
+// ToEngineeringString returns the decimal the same as java.math.BigDecimal.toEngineeringString(),
+// using engineering notation whose exponent is a multiple of three if an exponent is needed, e.g. 100E-9.
+func (d *Decimal) ToEngineeringString() string {
+	return d.toJavaString(true)
+}
+
+// toJavaString lays out the decimal as java.math.BigDecimal.layoutChars does.
+func (d *Decimal) toJavaString(engineering bool) string {
+	plain := d.ToBytes()
+	// coeff is the absolute unscaled value without leading zeros.
+	coeff := make([]byte, 0, len(plain))
+	for _, c := range plain {
+		if isDigit(c) && (len(coeff) > 0 || c != '0') {
+			coeff = append(coeff, c)
+		}
+	}
+	zero := len(coeff) == 0
+	if zero {
+		coeff = append(coeff, '0')
+	}
+	adjusted := len(coeff) - 1 - int(d.digitsFrac)
+	if adjusted >= -6 {
+		return string(plain)
+	}
+
+	str := make([]byte, 0, len(coeff)+8)
+	if d.negative && !zero {
+		str = append(str, '-')
+	}
+	if !engineering {
+		str = append(str, coeff[0])
+		if len(coeff) > 1 {
+			str = append(str, '.')
+			str = append(str, coeff[1:]...)
+		}
+	} else {
+		// sig is the number of digits before the point.
+		sig := adjusted % 3
+		if sig < 0 {
+			sig += 3
+		}
+		adjusted -= sig
+		sig++
+		switch {
+		case zero:
+			if sig > 1 {
+				str = append(str, "0.00"[:6-sig]...)
+				adjusted += 3
+			} else {
+				str = append(str, '0')
+			}
+		case sig >= len(coeff):
+			str = append(str, coeff...)
+			for i := sig - len(coeff); i > 0; i-- {
+				str = append(str, '0')
+			}
+		default:
+			str = append(str, coeff[:sig]...)
+			str = append(str, '.')
+			str = append(str, coeff[sig:]...)
+		}
+	}
+	if adjusted != 0 {
+		str = append(str, 'E')
+		if adjusted > 0 {
+			str = append(str, '+')
+		}
+		str = strconv.AppendInt(str, int64(adjusted), 10)
+	}
+	return string(str)
+}
+
 // FromBytes parses decimal from string.
 func (d *Decimal) FromString(str string) error {
 	return d.FromBytes([]byte(str))
@@ -398,6 +529,7 @@ func (d *Decimal) FromString(str string) error {
 
 // FromBytes parses decimal from bytes.
 func (d *Decimal) FromBytes(str []byte) error {
+	defer d.updateJavaValue()
 	for i := 0; i < len(str); i++ {
 		if !isSpace(str[i]) {
 			str = str[i:]
@@ -415,6 +547,13 @@ func (d *Decimal) FromBytes(str []byte) error {
 	case '+':
 		str = str[1:]
 	}
+	// the leading zeros take no word, so that 0.x parses all the digits that x * 10^-n holds.
+	for len(str) > 1 && str[0] == '0' && isDigit(str[1]) {
+		str = str[1:]
+	}
+	if len(str) > 2 && str[0] == '0' && str[1] == '.' && isDigit(str[2]) {
+		str = str[1:]
+	}
 	var strIdx int
 	for strIdx < len(str) && isDigit(str[strIdx]) {
 		strIdx++
@@ -506,6 +645,7 @@ func (d *Decimal) FromBytes(str []byte) error {
 			err = ErrTruncated
 		}
 		if err != ErrOverflow {
+			fracBeforeShift := int(d.digitsFrac)
 			shiftErr := d.Shift(int(exponent))
 			if shiftErr != nil {
 				if shiftErr == ErrOverflow {
@@ -514,6 +654,9 @@ func (d *Decimal) FromBytes(str []byte) error {
 					d.negative = negative
 				}
 				err = shiftErr
+			} else if exponent < 0 && d.IsZero() {
+				// keep the scale of zero as java does, e.g. 0E-3 is 0.000
+				d.digitsFrac = int8(myMin(fracBeforeShift-int(exponent), wordBufLen*digitsPerWord))
 			}
 		}
 	}
@@ -535,11 +678,12 @@ func (d *Decimal) FromBytes(str []byte) error {
 // shift < 0 means right shift. In fact it is multiplying on 10^shift.
 //
 // RETURN
-//   eDecOK          OK
-//   eDecOverflow    operation lead to overflow, number is untoched
-//   eDecTruncated   number was rounded to fit into buffer
 //
+//	eDecOK          OK
+//	eDecOverflow    operation lead to overflow, number is untoched
+//	eDecTruncated   number was rounded to fit into buffer
 func (d *Decimal) Shift(shift int) error {
+	defer d.updateJavaValue()
 	var err error
 	if shift == 0 {
 		return nil
@@ -698,10 +842,10 @@ func (d *Decimal) Shift(shift int) error {
 }
 
 /*
-  digitBounds returns bounds of decimal digits in the number.
+digitBounds returns bounds of decimal digits in the number.
 
-      start - index (from 0 ) of first decimal digits.
-      end   - index of position just after last decimal digit.
+	start - index (from 0 ) of first decimal digits.
+	end   - index of position just after last decimal digit.
 */
 func (d *Decimal) digitBounds() (start, end int) {
 	var i int
@@ -747,14 +891,15 @@ func (d *Decimal) digitBounds() (start, end int) {
 }
 
 /*
-  doMiniLeftShift does left shift for alignment of data in buffer.
+doMiniLeftShift does left shift for alignment of data in buffer.
+
+	shift   number of decimal digits on which it should be shifted
+	beg/end bounds of decimal digits (see digitsBounds())
 
-    shift   number of decimal digits on which it should be shifted
-    beg/end bounds of decimal digits (see digitsBounds())
+NOTE
 
-  NOTE
-    Result fitting in the buffer should be garanted.
-    'shift' have to be from 1 to digitsPerWord-1 (inclusive)
+	Result fitting in the buffer should be garanted.
+	'shift' have to be from 1 to digitsPerWord-1 (inclusive)
 */
 func (d *Decimal) doMiniLeftShift(shift, beg, end int) {
 	bufFrom := beg / digitsPerWord
@@ -771,14 +916,15 @@ func (d *Decimal) doMiniLeftShift(shift, beg, end int) {
 }
 
 /*
-  doMiniRightShift does right shift for alignment of data in buffer.
+doMiniRightShift does right shift for alignment of data in buffer.
 
-    shift   number of decimal digits on which it should be shifted
-    beg/end bounds of decimal digits (see digitsBounds())
+	shift   number of decimal digits on which it should be shifted
+	beg/end bounds of decimal digits (see digitsBounds())
 
-  NOTE
-    Result fitting in the buffer should be garanted.
-    'shift' have to be from 1 to digitsPerWord-1 (inclusive)
+NOTE
+
+	Result fitting in the buffer should be garanted.
+	'shift' have to be from 1 to digitsPerWord-1 (inclusive)
 */
 func (d *Decimal) doMiniRightShift(shift, beg, end int) {
 	bufFrom := (end - 1) / digitsPerWord
@@ -794,21 +940,71 @@ func (d *Decimal) doMiniRightShift(shift, beg, end int) {
 	d.wordBuf[bufFrom] = d.wordBuf[bufFrom] / powers10[shift]
 }
 
+// needIncrement reports whether the magnitude of a decimal must be incremented by one unit
+// of the last kept digit when the remaining digits are discarded.
+//
+//	negative		- sign of the decimal
+//	lastDigit		- the last kept digit
+//	firstDigit	- the first discarded digit
+//	sticky		- whether any discarded digit after firstDigit is not zero
+func (m RoundMode) needIncrement(negative bool, lastDigit, firstDigit int32, sticky bool) bool {
+	inexact := firstDigit != 0 || sticky
+	switch m {
+	case ModeUp:
+		return inexact
+	case ModeCeiling:
+		return inexact && !negative
+	case ModeFloor:
+		return inexact && negative
+	case ModeHalfUp, ModeHalfEven:
+		return firstDigit >= 5
+	case ModeHalfDown:
+		return firstDigit > 5 || (firstDigit == 5 && sticky)
+	case ModeHalfToEven:
+		return firstDigit > 5 || (firstDigit == 5 && (sticky || lastDigit%2 == 1))
+	}
+	// ModeTruncate, and ModeUnnecessary which has been checked to be exact.
+	return false
+}
+
+// hasNonZeroWords checks whether any word in wordBuf[start:end] is not zero.
+func (d *Decimal) hasNonZeroWords(start, end int) bool {
+	for i := myMax(start, 0); i < end && i < wordBufLen; i++ {
+		if d.wordBuf[i] != 0 {
+			return true
+		}
+	}
+	return false
+}
+
 // Round rounds the decimal to "frac" digits.
 //
-//    to			- result buffer. d == to is allowed
-//    frac			- to what position after fraction point to round. can be negative!
-//    roundMode		- round to nearest even or truncate
-// 			ModeHalfEven rounds normally.
-// 			Truncate just truncates the decimal.
+//	   to			- result buffer. d == to is allowed
+//	   frac			- to what position after fraction point to round. can be negative!
+//	   roundMode		- one of the java.math.RoundingMode equivalents:
+//				ModeUp, ModeDown(ModeTruncate), ModeCeiling, ModeFloor,
+//				ModeHalfUp, ModeHalfDown, ModeHalfToEven and ModeUnnecessary,
+//				and ModeHalfEven which is ModeHalfUp.
 //
 // NOTES
-//  scale can be negative !
-//  one TRUNCATED error (line XXX below) isn't treated very logical :(
+//
+//	scale can be negative !
+//	one TRUNCATED error (line XXX below) isn't treated very logical :(
 //
 // RETURN VALUE
-//  eDecOK/eDecTruncated
+//
+//	eDecOK/eDecTruncated/eDecOverflow, or ErrRoundingNecessary if roundMode is
+//	ModeUnnecessary and some non-zero digits would be discarded, "to" is untouched then.
 func (d *Decimal) Round(to *Decimal, frac int, roundMode RoundMode) (err error) {
+	defer to.updateJavaValue()
+	if roundMode == ModeUnnecessary {
+		start, end := d.digitBounds()
+		if start != end && end-digitsToWords(int(d.digitsInt))*digitsPerWord > frac {
+			return ErrRoundingNecessary
+		}
+		roundMode = ModeTruncate
+	}
+
 	// wordsFracTo is the number of fraction words in buffer.
 	wordsFracTo := (frac + 1) / digitsPerWord
 	if frac > 0 {
@@ -817,17 +1013,25 @@ func (d *Decimal) Round(to *Decimal, frac int, roundMode RoundMode) (err error)
 	wordsFrac := digitsToWords(int(d.digitsFrac))
 	wordsInt := digitsToWords(int(d.digitsInt))
 
-	roundDigit := int32(roundMode)
-	/* TODO - fix this code as it won't work for CEILING mode */
-
 	if wordsInt+wordsFracTo > wordBufLen {
 		wordsFracTo = wordBufLen - wordsInt
 		frac = wordsFracTo * digitsPerWord
 		err = ErrTruncated
 	}
 	if int(d.digitsInt)+frac < 0 {
+		// All digits are discarded, the result is either 0 or one unit at the rounding position.
+		negative := d.negative
+		if !roundMode.needIncrement(negative, 0, 0, !d.IsZero()) {
+			*to = zeroBigDecimal
+			return nil
+		}
 		*to = zeroBigDecimal
-		return nil
+		to.FromUint(1)
+		if err = to.Shift(-frac); err == ErrOverflow {
+			maxDecimal(wordBufLen*digitsPerWord, 0, to)
+		}
+		to.negative = negative
+		return err
 	}
 	if to != d {
 		copy(to.wordBuf[:], d.wordBuf[:])
@@ -853,30 +1057,15 @@ func (d *Decimal) Round(to *Decimal, frac int, roundMode RoundMode) (err error)
 
 	// Do increment.
 	toIdx := wordsInt + wordsFracTo - 1
+	wordsEnd := wordsInt + wordsFrac
 	if frac == wordsFracTo*digitsPerWord {
-		doInc := false
-		switch roundMode {
-		// Notice: No support for ceiling mode now.
-		case modeCeiling:
-			// If any word after scale is not zero, do increment.
-			// e.g ceiling 3.0001 to scale 1, gets 3.1
-			idx := toIdx + (wordsFrac - wordsFracTo)
-			for idx > toIdx {
-				if d.wordBuf[idx] != 0 {
-					doInc = true
-					break
-				}
-				idx--
-			}
-		case ModeHalfEven:
-			digAfterScale := d.wordBuf[toIdx+1] / digMask // the first digit after scale.
-			// If first digit after scale is 5 and round even, do increment if digit at scale is odd.
-			doInc = (digAfterScale > 5) || (digAfterScale == 5)
-		case ModeTruncate:
-			// Never round, just truncate.
-			doInc = false
-		}
-		if doInc {
+		var lastDigit int32
+		if toIdx >= 0 {
+			lastDigit = d.wordBuf[toIdx] % 10
+		}
+		firstDigit := d.wordBuf[toIdx+1] / digMask // the first digit after scale.
+		sticky := d.wordBuf[toIdx+1]%digMask != 0 || d.hasNonZeroWords(toIdx+2, wordsEnd)
+		if roundMode.needIncrement(d.negative, lastDigit, firstDigit, sticky) {
 			if toIdx >= 0 {
 				to.wordBuf[toIdx]++
 			} else {
@@ -888,11 +1077,17 @@ func (d *Decimal) Round(to *Decimal, frac int, roundMode RoundMode) (err error)
 			return nil
 		}
 	} else {
-		/* TODO - fix this code as it won't work for CEILING mode */
 		pos := wordsFracTo*digitsPerWord - frac - 1
 		shiftedNumber := to.wordBuf[toIdx] / powers10[pos]
 		digAfterScale := shiftedNumber % 10
-		if digAfterScale > roundDigit || (roundDigit == 5 && digAfterScale == 5) {
+		var lastDigit int32
+		if pos+1 < digitsPerWord {
+			lastDigit = shiftedNumber / 10 % 10
+		} else if toIdx > 0 {
+			lastDigit = to.wordBuf[toIdx-1] % 10
+		}
+		sticky := to.wordBuf[toIdx]%powers10[pos] != 0 || d.hasNonZeroWords(toIdx+1, wordsEnd)
+		if roundMode.needIncrement(d.negative, lastDigit, digAfterScale, sticky) {
 			shiftedNumber += 10
 		}
 		to.wordBuf[toIdx] = powers10[pos] * (shiftedNumber - digAfterScale)
@@ -996,6 +1191,7 @@ func (d *Decimal) FromInt(val int64) *Decimal {
 
 // FromUint sets the decimal value from uint64.
 func (d *Decimal) FromUint(val uint64) *Decimal {
+	defer d.updateJavaValue()
 	x := val
 	wordIdx := 1
 	for x >= wordBase {
@@ -1079,10 +1275,128 @@ func (d *Decimal) ToUint() (uint64, error) {
 	return x, nil
 }
 
-// FromFloat64 creates a decimal from float64 value.
+// FromUnscaledBigInt sets the decimal value to unscaled * 10^(-scale), the same as
+// java.math.BigDecimal(BigInteger unscaledVal, int scale). A negative scale is the
+// exponent of the scientific notation, e.g. (123, -2) means 1.23E+4.
+//
+// RETURN VALUE
+//
+//	ErrOverflow     the integer part does not fit into the word buffer, d is set to the max decimal
+//	ErrTruncated    the fraction part was truncated to fit into the word buffer
+func (d *Decimal) FromUnscaledBigInt(unscaled *big.Int, scale int32) error {
+	*d = zeroBigDecimal
+	digits := new(big.Int).Abs(unscaled).Text(10)
+	maxDigits := wordBufLen * digitsPerWord
+	if unscaled.Sign() != 0 && int64(len(digits))-int64(scale) > int64(maxDigits) {
+		maxDecimal(maxDigits, 0, d)
+		d.negative = unscaled.Sign() < 0
+		d.updateJavaValue()
+		return ErrOverflow
+	}
+	if int64(scale)-int64(len(digits)) > int64(maxDigits) {
+		// all the digits are beyond the word buffer.
+		d.updateJavaValue()
+		if unscaled.Sign() != 0 {
+			return ErrTruncated
+		}
+		return nil
+	}
+
+	str := make([]byte, 0, len(digits)+3)
+	if unscaled.Sign() < 0 {
+		str = append(str, '-')
+	}
+	switch digitsInt := len(digits) - int(scale); {
+	case scale <= 0:
+		str = append(str, digits...)
+		for i := int32(0); i < -scale && unscaled.Sign() != 0; i++ {
+			str = append(str, '0')
+		}
+	case digitsInt > 0:
+		str = append(str, digits[:digitsInt]...)
+		str = append(str, '.')
+		str = append(str, digits[digitsInt:]...)
+	default:
+		str = append(str, '0', '.')
+		for ; digitsInt < 0; digitsInt++ {
+			str = append(str, '0')
+		}
+		str = append(str, digits...)
+	}
+	return d.FromBytes(str)
+}
+
+// ToUnscaledBigInt returns the unscaled value and the scale of the decimal, the same as
+// java.math.BigDecimal.unscaledValue() and scale(). The scale is never negative.
+func (d *Decimal) ToUnscaledBigInt() (unscaled *big.Int, scale int32) {
+	wordsInt := digitsToWords(int(d.digitsInt))
+	wordsFrac := digitsToWords(int(d.digitsFrac))
+	unscaled = new(big.Int)
+	word := new(big.Int)
+	for i := 0; i < wordsInt+wordsFrac && i < wordBufLen; i++ {
+		unscaled.Mul(unscaled, bigWordBase)
+		unscaled.Add(unscaled, word.SetInt64(int64(d.wordBuf[i])))
+	}
+	// drop the digits in the last word after digitsFrac.
+	if trailing := wordsFrac*digitsPerWord - int(d.digitsFrac); trailing > 0 {
+		unscaled.Quo(unscaled, word.SetInt64(int64(powers10[trailing])))
+	}
+	if d.negative {
+		unscaled.Neg(unscaled)
+	}
+	return unscaled, int32(d.digitsFrac)
+}
+
+// FromFloat64 creates a decimal from float64 value, it is the shortest decimal that converts back to f,
+// e.g. 0.1. Use FromFloat64Exact for the full binary expansion of f.
 func (d *Decimal) FromFloat64(f float64) error {
-	s := strconv.FormatFloat(f, 'g', -1, 64)
-	return d.FromBytes([]byte(s))
+	return d.fromShortestFloat(f, 64)
+}
+
+// FromFloat32 creates a decimal from float32 value, it is the shortest decimal that converts back to f.
+func (d *Decimal) FromFloat32(f float32) error {
+	return d.fromShortestFloat(float64(f), 32)
+}
+
+func (d *Decimal) fromShortestFloat(f float64, bitSize int) error {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return ErrBadNumber
+	}
+	var buf [32]byte
+	return d.FromBytes(strconv.AppendFloat(buf[:0], f, 'g', -1, bitSize))
+}
+
+// FromFloat64Exact creates a decimal from the exact value of f, e.g. 0.1 is
+// 0.1000000000000000055511151231257827021181583404541015625.
+// The binary expansion has up to 767 significant digits, if it does not fit into the 81 digits of the
+// word buffer, it returns the same as FromUnscaledBigInt: ErrOverflow with the max decimal if the integer
+// part does not fit, e.g. 1e308, or ErrTruncated with the fraction digits that fit, e.g. 1e-300 is 0.
+func (d *Decimal) FromFloat64Exact(f float64) error {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return ErrBadNumber
+	}
+	// f = mantissa * 2^exp, and 2^-n = 5^n / 10^n.
+	frac, exp := math.Frexp(f)
+	mantissa := int64(frac * (1 << 53))
+	exp -= 53
+	if mantissa == 0 {
+		return d.FromUnscaledBigInt(new(big.Int), 0)
+	}
+	for mantissa%2 == 0 {
+		mantissa /= 2
+		exp++
+	}
+	unscaled := big.NewInt(mantissa)
+	if exp >= 0 {
+		return d.FromUnscaledBigInt(unscaled.Lsh(unscaled, uint(exp)), 0)
+	}
+	unscaled.Mul(unscaled, new(big.Int).Exp(big.NewInt(5), big.NewInt(int64(-exp)), nil))
+	return d.FromUnscaledBigInt(unscaled, int32(-exp))
+}
+
+// FromFloat32Exact creates a decimal from the exact value of f.
+func (d *Decimal) FromFloat32Exact(f float32) error {
+	return d.FromFloat64Exact(float64(f))
 }
 
 // ToFloat64 converts decimal to float64 value.
@@ -1094,83 +1408,114 @@ func (d *Decimal) ToFloat64() (float64, error) {
 	return f, err
 }
 
+// ToFloat32 converts decimal to float32 value, ErrOverflow is returned if it is infinite.
+func (d *Decimal) ToFloat32() (float32, error) {
+	f, err := strconv.ParseFloat(d.String(), 32)
+	if err != nil {
+		err = ErrOverflow
+	}
+	return float32(f), err
+}
+
+// ToFloat64Exact converts all the digits of d to the nearest float64, and reports whether it is
+// the exact value of d, i.e. exact is false if the precision is lost. Unlike ToFloat64, the digits
+// beyond the result frac of String are kept, e.g. the quotient of DecimalDiv. A decimal never
+// overflows float64.
+func (d *Decimal) ToFloat64Exact() (f float64, exact bool) {
+	unscaled, scale := d.ToUnscaledBigInt()
+	if scale == 0 && unscaled.IsInt64() {
+		// the integers of 53 bits are exact.
+		if i := unscaled.Int64(); i < 1<<53 && i > -1<<53 {
+			return float64(i), true
+		}
+	}
+	return new(big.Rat).SetFrac(unscaled, bigPow10(int(scale))).Float64()
+}
+
+// ToFloat32Exact converts all the digits of d to the nearest float32 like ToFloat64Exact,
+// it is infinite if d overflows float32.
+func (d *Decimal) ToFloat32Exact() (f float32, exact bool) {
+	unscaled, scale := d.ToUnscaledBigInt()
+	return new(big.Rat).SetFrac(unscaled, bigPow10(int(scale))).Float32()
+}
+
 /*
 ToBin converts decimal to its binary fixed-length representation
 two representations of the same length can be compared with memcmp
 with the correct -1/0/+1 result
 
-  PARAMS
-		precision/frac - if precision is 0, internal value of the decimal will be used,
-		then the encoded value is not memory comparable.
+	  PARAMS
+			precision/frac - if precision is 0, internal value of the decimal will be used,
+			then the encoded value is not memory comparable.
 
-  NOTE
-    the buffer is assumed to be of the size decimalBinSize(precision, frac)
+	  NOTE
+	    the buffer is assumed to be of the size decimalBinSize(precision, frac)
 
-  RETURN VALUE
-  	bin     - binary value
-    errCode - eDecOK/eDecTruncate/eDecOverflow
+	  RETURN VALUE
+	  	bin     - binary value
+	    errCode - eDecOK/eDecTruncate/eDecOverflow
 
-  DESCRIPTION
-    for storage decimal numbers are converted to the "binary" format.
+	  DESCRIPTION
+	    for storage decimal numbers are converted to the "binary" format.
 
-    This format has the following properties:
-      1. length of the binary representation depends on the {precision, frac}
-      as provided by the caller and NOT on the digitsInt/digitsFrac of the decimal to
-      convert.
-      2. binary representations of the same {precision, frac} can be compared
-      with memcmp - with the same result as DecimalCompare() of the original
-      decimals (not taking into account possible precision loss during
-      conversion).
+	    This format has the following properties:
+	      1. length of the binary representation depends on the {precision, frac}
+	      as provided by the caller and NOT on the digitsInt/digitsFrac of the decimal to
+	      convert.
+	      2. binary representations of the same {precision, frac} can be compared
+	      with memcmp - with the same result as DecimalCompare() of the original
+	      decimals (not taking into account possible precision loss during
+	      conversion).
 
-    This binary format is as follows:
-      1. First the number is converted to have a requested precision and frac.
-      2. Every full digitsPerWord digits of digitsInt part are stored in 4 bytes
-         as is
-      3. The first digitsInt % digitesPerWord digits are stored in the reduced
-         number of bytes (enough bytes to store this number of digits -
-         see dig2bytes)
-      4. same for frac - full word are stored as is,
-         the last frac % digitsPerWord digits - in the reduced number of bytes.
-      5. If the number is negative - every byte is inversed.
-      5. The very first bit of the resulting byte array is inverted (because
-         memcmp compares unsigned bytes, see property 2 above)
+	    This binary format is as follows:
+	      1. First the number is converted to have a requested precision and frac.
+	      2. Every full digitsPerWord digits of digitsInt part are stored in 4 bytes
+	         as is
+	      3. The first digitsInt % digitesPerWord digits are stored in the reduced
+	         number of bytes (enough bytes to store this number of digits -
+	         see dig2bytes)
+	      4. same for frac - full word are stored as is,
+	         the last frac % digitsPerWord digits - in the reduced number of bytes.
+	      5. If the number is negative - every byte is inversed.
+	      5. The very first bit of the resulting byte array is inverted (because
+	         memcmp compares unsigned bytes, see property 2 above)
 
-    Example:
+	    Example:
 
-      1234567890.1234
+	      1234567890.1234
 
-    internally is represented as 3 words
+	    internally is represented as 3 words
 
-      1 234567890 123400000
+	      1 234567890 123400000
 
-    (assuming we want a binary representation with precision=14, frac=4)
-    in hex it's
+	    (assuming we want a binary representation with precision=14, frac=4)
+	    in hex it's
 
-      00-00-00-01  0D-FB-38-D2  07-5A-EF-40
+	      00-00-00-01  0D-FB-38-D2  07-5A-EF-40
 
-    now, middle word is full - it stores 9 decimal digits. It goes
-    into binary representation as is:
+	    now, middle word is full - it stores 9 decimal digits. It goes
+	    into binary representation as is:
 
 
-      ...........  0D-FB-38-D2 ............
+	      ...........  0D-FB-38-D2 ............
 
-    First word has only one decimal digit. We can store one digit in
-    one byte, no need to waste four:
+	    First word has only one decimal digit. We can store one digit in
+	    one byte, no need to waste four:
 
-                01 0D-FB-38-D2 ............
+	                01 0D-FB-38-D2 ............
 
-    now, last word. It's 123400000. We can store 1234 in two bytes:
+	    now, last word. It's 123400000. We can store 1234 in two bytes:
 
-                01 0D-FB-38-D2 04-D2
+	                01 0D-FB-38-D2 04-D2
 
-    So, we've packed 12 bytes number in 7 bytes.
-    And now we invert the highest bit to get the final result:
+	    So, we've packed 12 bytes number in 7 bytes.
+	    And now we invert the highest bit to get the final result:
 
-                81 0D FB 38 D2 04 D2
+	                81 0D FB 38 D2 04 D2
 
-    And for -1234567890.1234 it would be
+	    And for -1234567890.1234 it would be
 
-                7E F2 04 C7 2D FB 2D
+	                7E F2 04 C7 2D FB 2D
 */
 func (d *Decimal) ToBin(precision, frac int) ([]byte, error) {
 	if precision > digitsPerWord*maxWordBufLen || precision < 0 || frac > maxDecimalScale || frac < 0 {
@@ -1285,21 +1630,156 @@ func (d *Decimal) ToBin(precision, frac int) ([]byte, error) {
 // ToHashKey removes the leading and trailing zeros and generates a hash key.
 // Two Decimals dec0 and dec1 with different fraction will generate the same hash keys if dec0.Compare(dec1) == 0.
 func (d *Decimal) ToHashKey() ([]byte, error) {
-	_, digitsInt := d.removeLeadingZeros()
-	_, digitsFrac := d.removeTrailingZeros()
-	prec := digitsInt + digitsFrac
+	// ToBin reports ErrTruncated whenever frac is less than digitsFrac, even if only zeros are
+	// removed, so the trailing zeros are stripped first, and an error of ToBin is a real one then.
+	stripped := d.StripTrailingZeros()
+	_, digitsInt := stripped.removeLeadingZeros()
+	prec := digitsInt + int(stripped.digitsFrac)
 	if prec == 0 { // zeroDecimal
 		prec = 1
 	}
-	buf, err := d.ToBin(prec, digitsFrac)
-	if err == ErrTruncated {
-		// This err is caused by shorter digitsFrac;
-		// After removing the trailing zeros from a Decimal,
-		// so digitsFrac may be less than the real digitsFrac of the Decimal,
-		// thus ErrTruncated may be raised, we can ignore it here.
-		err = nil
+	return stripped.ToBin(prec, int(stripped.digitsFrac))
+}
+
+// The tags of the ordered keys, the negative decimals sort before zero and the positive ones after.
+const (
+	orderedKeyNegative byte = 0x01
+	orderedKeyZero     byte = 0x02
+	orderedKeyPositive byte = 0x03
+)
+
+// ToOrderedKey generates a variable-length key of d, the byte-wise comparison of two keys is the same as
+// Compare of the decimals, so the keys can be stored by an ordered key-value store. The key is self-describing,
+// FromOrderedKey restores the decimal without knowing the precision and the frac, and as no key is a prefix
+// of another, it can be followed by other fields in a composite key.
+//
+// Like ToHashKey, the leading and trailing zeros are removed, e.g. 1.50 and 1.5 have the same key.
+//
+// A non-zero decimal is normalized to 0.DDD * 10^E, and the key is a tag byte, the byte E+128 and the
+// digits D in pairs, each pair p is the byte 2p+1, except that the last one is 2p. The bytes after the tag
+// are inverted for a negative decimal. E.g. 12.5 is 03 82 19 64, and -12.5 is 01 7D E6 9B.
+func (d *Decimal) ToOrderedKey() []byte {
+	str := d.ToBytes()
+	if str[0] == '-' {
+		str = str[1:]
+	}
+	digits := make([]byte, 0, len(str))
+	exponent := 0
+	for i, c := range str {
+		if c == '.' {
+			break
+		}
+		exponent = i + 1
+	}
+	for _, c := range str {
+		if c != '.' {
+			digits = append(digits, c)
+		}
+	}
+	for len(digits) > 0 && digits[0] == '0' {
+		digits = digits[1:]
+		exponent--
+	}
+	for len(digits) > 0 && digits[len(digits)-1] == '0' {
+		digits = digits[:len(digits)-1]
+	}
+	if len(digits) == 0 {
+		return []byte{orderedKeyZero}
+	}
+
+	key := make([]byte, 0, 2+(len(digits)+1)/2)
+	key = append(key, orderedKeyPositive, byte(exponent+128))
+	for i := 0; i < len(digits); i += 2 {
+		pair := (digits[i] - '0') * 10
+		if i+1 < len(digits) {
+			pair += digits[i+1] - '0'
+		}
+		if i+2 < len(digits) {
+			key = append(key, pair*2+1)
+		} else {
+			key = append(key, pair*2)
+		}
+	}
+	if d.negative {
+		key[0] = orderedKeyNegative
+		for i := 1; i < len(key); i++ {
+			key[i] = ^key[i]
+		}
+	}
+	return key
+}
+
+// FromOrderedKey restores the decimal from the head of key generated by ToOrderedKey, and returns the size
+// of the decimal key. It returns ErrBadNumber if the key is malformed.
+func (d *Decimal) FromOrderedKey(key []byte) (keySize int, err error) {
+	if len(key) == 0 {
+		return 0, ErrBadNumber
+	}
+	var mask byte
+	switch key[0] {
+	case orderedKeyZero:
+		*d = zeroBigDecimal
+		d.updateJavaValue()
+		return 1, nil
+	case orderedKeyNegative:
+		mask = 0xff
+	case orderedKeyPositive:
+	default:
+		return 0, ErrBadNumber
+	}
+	if len(key) < 3 {
+		return 0, ErrBadNumber
+	}
+	exponent := int(key[1]^mask) - 128
+	digits := make([]byte, 0, 2*len(key))
+	for keySize = 2; ; keySize++ {
+		if keySize == len(key) {
+			return 0, ErrBadNumber
+		}
+		b := key[keySize] ^ mask
+		pair := b / 2
+		if pair > 99 {
+			return 0, ErrBadNumber
+		}
+		digits = append(digits, '0'+pair/10, '0'+pair%10)
+		if b%2 == 0 {
+			keySize++
+			break
+		}
+	}
+	// the digits are normalized without the leading and trailing zeros.
+	if digits[0] == '0' || key[keySize-1]^mask == 0 {
+		return 0, ErrBadNumber
+	}
+	for digits[len(digits)-1] == '0' {
+		digits = digits[:len(digits)-1]
+	}
+
+	str := make([]byte, 0, len(digits)+3)
+	if mask != 0 {
+		str = append(str, '-')
+	}
+	switch {
+	case exponent <= 0:
+		str = append(str, '0', '.')
+		for i := exponent; i < 0; i++ {
+			str = append(str, '0')
+		}
+		str = append(str, digits...)
+	case exponent < len(digits):
+		str = append(str, digits[:exponent]...)
+		str = append(str, '.')
+		str = append(str, digits[exponent:]...)
+	default:
+		str = append(str, digits...)
+		for i := len(digits); i < exponent; i++ {
+			str = append(str, '0')
+		}
+	}
+	if err = d.FromBytes(str); err != nil {
+		return 0, err
 	}
-	return buf, err
+	return keySize, nil
 }
 
 // PrecisionAndFrac returns the internal precision and frac number.
@@ -1327,6 +1807,7 @@ func (d *Decimal) IsZero() bool {
 
 // FromBin Restores decimal from its binary fixed-length representation.
 func (d *Decimal) FromBin(bin []byte, precision, frac int) (binSize int, err error) {
+	defer d.updateJavaValue()
 	if len(bin) == 0 {
 		*d = zeroBigDecimal
 		return 0, ErrBadNumber
@@ -1351,6 +1832,10 @@ func (d *Decimal) FromBin(bin []byte, precision, frac int) (binSize int, err err
 		mask = 0
 	}
 	binSize = decimalBinSize(precision, frac)
+	if len(bin) < binSize {
+		*d = zeroBigDecimal
+		return 0, ErrBadNumber
+	}
 	dCopy := make([]byte, 40)
 	dCopy = dCopy[:binSize]
 	copy(dCopy, bin)
@@ -1376,7 +1861,7 @@ func (d *Decimal) FromBin(bin []byte, precision, frac int) (binSize int, err err
 		x := readWord(bin[binIdx:], i)
 		binIdx += i
 		d.wordBuf[wordIdx] = x ^ mask
-		if uint64(d.wordBuf[wordIdx]) >= uint64(powers10[leadingDigits+1]) {
+		if uint32(d.wordBuf[wordIdx]) >= uint32(powers10[leadingDigits]) {
 			*d = zeroBigDecimal
 			return binSize, ErrBadNumber
 		}
@@ -1410,12 +1895,12 @@ func (d *Decimal) FromBin(bin []byte, precision, frac int) (binSize int, err err
 
 	if trailingDigits > 0 {
 		i := dig2bytes[trailingDigits]
-		x := readWord(bin[binIdx:], i)
-		d.wordBuf[wordIdx] = (x ^ mask) * powers10[digitsPerWord-trailingDigits]
-		if uint32(d.wordBuf[wordIdx]) > wordMax {
+		x := readWord(bin[binIdx:], i) ^ mask
+		if uint32(x) >= uint32(powers10[trailingDigits]) {
 			*d = zeroBigDecimal
 			return binSize, ErrBadNumber
 		}
+		d.wordBuf[wordIdx] = x * powers10[digitsPerWord-trailingDigits]
 	}
 
 	if d.digitsInt == 0 && d.digitsFrac == 0 {
@@ -1494,6 +1979,7 @@ func DecimalNeg(from *Decimal) *Decimal {
 		return &to
 	}
 	to.negative = !from.negative
+	to.updateJavaValue()
 	return &to
 }
 
@@ -1501,6 +1987,15 @@ func DecimalNeg(from *Decimal) *Decimal {
 // Note: DO NOT use `from1` or `from2` as `to` since the metadata
 // of `to` may be changed during evaluating.
 func DecimalAdd(from1, from2, to *Decimal) error {
+	defer to.updateJavaValue()
+	if addCompact(from1, from2, to, false) {
+		return nil
+	}
+	return addWords(from1, from2, to)
+}
+
+// addWords is DecimalAdd in the word form.
+func addWords(from1, from2, to *Decimal) error {
 	to.resultFrac = myMaxInt8(from1.resultFrac, from2.resultFrac)
 	if from1.negative == from2.negative {
 		return doAdd(from1, from2, to)
@@ -1511,6 +2006,15 @@ func DecimalAdd(from1, from2, to *Decimal) error {
 
 // DecimalSub subs one decimal from another, sets the result to 'to'.
 func DecimalSub(from1, from2, to *Decimal) error {
+	defer to.updateJavaValue()
+	if addCompact(from1, from2, to, true) {
+		return nil
+	}
+	return subWords(from1, from2, to)
+}
+
+// subWords is DecimalSub in the word form.
+func subWords(from1, from2, to *Decimal) error {
 	to.resultFrac = myMaxInt8(from1.resultFrac, from2.resultFrac)
 	if from1.negative == from2.negative {
 		_, err := doSub(from1, from2, to)
@@ -1822,23 +2326,37 @@ func maxDecimal(precision, frac int, to *Decimal) {
 /*
 DecimalMul multiplies two decimals.
 
-      from1, from2 - factors
-      to      - product
+	    from1, from2 - factors
+	    to      - product
 
-  RETURN VALUE
-    E_DEC_OK/E_DEC_TRUNCATED/E_DEC_OVERFLOW;
+	RETURN VALUE
+	  E_DEC_OK/E_DEC_TRUNCATED/E_DEC_OVERFLOW;
 
-  NOTES
-    in this implementation, with wordSize=4 we have digitsPerWord=9,
-    and 63-digit number will take only 7 words (basically a 7-digit
-    "base 999999999" number).  Thus there's no need in fast multiplication
-    algorithms, 7-digit numbers can be multiplied with a naive O(n*n)
-    method.
+	NOTES
+	  in this implementation, with wordSize=4 we have digitsPerWord=9,
+	  and 63-digit number will take only 7 words (basically a 7-digit
+	  "base 999999999" number).  Thus there's no need in fast multiplication
+	  algorithms, 7-digit numbers can be multiplied with a naive O(n*n)
+	  method.
 
-    XXX if this library is to be used with huge numbers of thousands of
-    digits, fast multiplication must be implemented.
+	  XXX if this library is to be used with huge numbers of thousands of
+	  digits, fast multiplication must be implemented.
+
+	  If the product has more words than the buffer, MySQL drops the low
+	  words of the factors, which loses the carries into the kept digits,
+	  e.g. it gives 0.000 for a product of 24 integer digits. Here the
+	  exact product is truncated to the kept digits instead.
 */
 func DecimalMul(from1, from2, to *Decimal) error {
+	defer to.updateJavaValue()
+	if mulCompact(from1, from2, to) {
+		return nil
+	}
+	return mulWords(from1, from2, to)
+}
+
+// mulWords is DecimalMul in the word form.
+func mulWords(from1, from2, to *Decimal) error {
 	var (
 		err         error
 		wordsInt1   = digitsToWords(int(from1.digitsInt))
@@ -1850,8 +2368,6 @@ func DecimalMul(from1, from2, to *Decimal) error {
 		idx1        = wordsInt1
 		idx2        = wordsInt2
 		idxTo       int
-		tmp1        = wordsIntTo
-		tmp2        = wordsFracTo
 	)
 	to.resultFrac = myMinInt8(from1.resultFrac+from2.resultFrac, maxDecimalScale)
 	wordsIntTo, wordsFracTo, err = fixWordCntError(wordsIntTo, wordsFracTo)
@@ -1868,26 +2384,18 @@ func DecimalMul(from1, from2, to *Decimal) error {
 		if to.digitsFrac > int8(wordsFracTo*digitsPerWord) {
 			to.digitsFrac = int8(wordsFracTo * digitsPerWord)
 		}
-		if to.digitsInt > int8(wordsIntTo*digitsPerWord) {
-			to.digitsInt = int8(wordsIntTo * digitsPerWord)
-		}
-		if tmp1 > wordsIntTo {
-			tmp1 -= wordsIntTo
-			tmp2 = tmp1 >> 1
-			wordsInt2 -= tmp1 - tmp2
-			wordsFrac1 = 0
-			wordsFrac2 = 0
-		} else {
-			tmp2 -= wordsFracTo
-			tmp1 = tmp2 >> 1
-			if wordsFrac1 <= wordsFrac2 {
-				wordsFrac1 -= tmp1
-				wordsFrac2 -= tmp2 - tmp1
-			} else {
-				wordsFrac2 -= tmp1
-				wordsFrac1 -= tmp2 - tmp1
-			}
-		}
+		// MySQL drops the low words of the operands here, which loses the carries into the kept
+		// digits, so the exact product is truncated instead.
+		v1, scale1 := from1.ToUnscaledBigInt()
+		v2, scale2 := from2.ToUnscaledBigInt()
+		v1.Mul(v1, v2)
+		v1.Quo(v1, bigPow10(int(scale1+scale2)-int(to.digitsFrac)))
+		resultFrac := to.resultFrac
+		if e := to.FromUnscaledBigInt(v1, int32(to.digitsFrac)); e != nil {
+			return e
+		}
+		to.resultFrac = resultFrac
+		return err
 	}
 	startTo := wordsIntTo + wordsFracTo - 1
 	start2 := idx2 + wordsFrac2 - 1
@@ -1967,6 +2475,7 @@ func DecimalMul(from1, from2, to *Decimal) error {
 // to       - quotient
 // fracIncr - increment of fraction
 func DecimalDiv(from1, from2, to *Decimal, fracIncr int) error {
+	defer to.updateJavaValue()
 	to.resultFrac = myMinInt8(from1.resultFrac+int8(fracIncr), maxDecimalScale)
 	return doDivMod(from1, from2, to, nil, fracIncr)
 }
@@ -1974,41 +2483,117 @@ func DecimalDiv(from1, from2, to *Decimal, fracIncr int) error {
 /*
 DecimalMod does modulus of two decimals.
 
-      from1   - dividend
-      from2   - divisor
-      to      - modulus
+	    from1   - dividend
+	    from2   - divisor
+	    to      - modulus
 
-  RETURN VALUE
-    E_DEC_OK/E_DEC_TRUNCATED/E_DEC_OVERFLOW/E_DEC_DIV_ZERO;
+	RETURN VALUE
+	  E_DEC_OK/E_DEC_TRUNCATED/E_DEC_OVERFLOW/E_DEC_DIV_ZERO;
 
-  NOTES
-    see do_div_mod()
+	NOTES
+	  see do_div_mod()
 
-  DESCRIPTION
-    the modulus R in    R = M mod N
+	DESCRIPTION
+	  the modulus R in    R = M mod N
 
-   is defined as
+	 is defined as
 
-     0 <= |R| < |M|
-     sign R == sign M
-     R = M - k*N, where k is integer
+	   0 <= |R| < |M|
+	   sign R == sign M
+	   R = M - k*N, where k is integer
 
-   thus, there's no requirement for M or N to be integers
+	 thus, there's no requirement for M or N to be integers
 */
 func DecimalMod(from1, from2, to *Decimal) error {
+	defer to.updateJavaValue()
 	to.resultFrac = myMaxInt8(from1.resultFrac, from2.resultFrac)
 	return doDivMod(from1, from2, nil, to, 0)
 }
 
+// DivisionMode chooses the rounding of the integral quotient of DecimalDivModWithMode, and so the sign
+// of the remainder, which is always from1 - quo * from2.
+type DivisionMode int32
+
+const (
+	// DivisionTruncated truncates the quotient towards zero, the remainder has the sign of the dividend.
+	// It is java.math.BigDecimal.divideToIntegralValue and remainder, and MySQL DIV and MOD.
+	DivisionTruncated DivisionMode = iota
+	// DivisionFloored rounds the quotient towards negative infinity, the remainder has the sign of the
+	// divisor, like the operators // and % of Python.
+	DivisionFloored
+	// DivisionEuclidean makes the remainder never negative, 0 <= rem < |from2|.
+	DivisionEuclidean
+)
+
+// DecimalDivMod computes the integral quotient and the remainder of from1 / from2 with one division,
+// and sets them to quo and rem, the same as DecimalDivModWithMode with DivisionTruncated.
+// Note: DO NOT use `from1` or `from2` as `quo` or `rem`.
+func DecimalDivMod(from1, from2, quo, rem *Decimal) error {
+	return DecimalDivModWithMode(from1, from2, quo, rem, DivisionTruncated)
+}
+
+// DecimalDivModWithMode computes the integral quotient and the remainder of from1 / from2 rounded with
+// mode, and sets them to quo and rem. Like java.math.BigDecimal.divideToIntegralValue, the quotient has
+// the preferred scale max(frac1 - frac2, 0), and the remainder has max(frac1, frac2) digits after the point.
+// It returns ErrDivByZero if from2 is zero, and ErrOverflow with the max decimal as the quotient if the
+// quotient has more than 81 digits.
+// Note: DO NOT use `from1` or `from2` as `quo` or `rem`.
+func DecimalDivModWithMode(from1, from2, quo, rem *Decimal, mode DivisionMode) error {
+	defer quo.updateJavaValue()
+	defer rem.updateJavaValue()
+	err := doDivMod(from1, from2, quo, rem, 0)
+	if err == ErrDivByZero {
+		return err
+	}
+	rem.resultFrac = myMaxInt8(from1.resultFrac, from2.resultFrac)
+	rem.negative = rem.negative && !rem.IsZero()
+
+	// adjust the truncated quotient by one if the remainder has the wrong sign.
+	var adjust bool
+	switch mode {
+	case DivisionFloored:
+		adjust = !rem.IsZero() && rem.negative != from2.negative
+	case DivisionEuclidean:
+		adjust = !rem.IsZero() && rem.negative
+	}
+	if adjust {
+		var q, r Decimal
+		one := NewDecFromInt(1)
+		if from2.negative && mode == DivisionEuclidean {
+			err = firstErr(err, DecimalAdd(quo, one, &q))
+			err = firstErr(err, DecimalSub(rem, from2, &r))
+		} else {
+			err = firstErr(err, DecimalSub(quo, one, &q))
+			err = firstErr(err, DecimalAdd(rem, from2, &r))
+		}
+		*quo, *rem = q, r
+	}
+
+	scale := myMax(int(from1.digitsFrac)-int(from2.digitsFrac), 0)
+	err = firstErr(err, quo.Round(quo, scale, ModeTruncate))
+	quo.resultFrac = int8(scale)
+	return err
+}
+
+func firstErr(err, next error) error {
+	if err != nil {
+		return err
+	}
+	return next
+}
+
 func doDivMod(from1, from2, to, mod *Decimal, fracIncr int) error {
 	var (
 		frac1 = digitsToWords(int(from1.digitsFrac)) * digitsPerWord
 		prec1 = int(from1.digitsInt) + frac1
 		frac2 = digitsToWords(int(from2.digitsFrac)) * digitsPerWord
 		prec2 = int(from2.digitsInt) + frac2
+		// quo gets the integral quotient if both to and mod are given.
+		quo    *Decimal
+		quoErr error
 	)
 	if mod != nil {
-		to = mod
+		quo, to = to, mod
 	}
 
 	/* removing all the leading zeros */
@@ -2035,6 +2620,9 @@ func doDivMod(from1, from2, to, mod *Decimal, fracIncr int) error {
 	if prec1 <= 0 {
 		/* short-circuit everything: from1 == 0 */
 		*to = zeroBigDecimal
+		if quo != nil {
+			*quo = zeroBigDecimal
+		}
 		return nil
 	}
 	prec1 -= countLeadingZeroes((prec1-1)%digitsPerWord, from1.wordBuf[idx1])
@@ -2065,6 +2653,10 @@ func doDivMod(from1, from2, to, mod *Decimal, fracIncr int) error {
 		// digitsInt=from2.digitsInt
 		to.negative = from1.negative
 		to.digitsFrac = myMaxInt8(from1.digitsFrac, from2.digitsFrac)
+		if quo != nil {
+			*quo = zeroBigDecimal
+			quo.digitsInt = int8(myMin(wordsIntTo, wordBufLen) * digitsPerWord)
+		}
 	} else {
 		wordsFracTo = digitsToWords(frac1 + frac2 + fracIncr)
 		wordsIntTo, wordsFracTo, err = fixWordCntError(wordsIntTo, wordsFracTo)
@@ -2179,10 +2771,22 @@ func doDivMod(from1, from2, to, mod *Decimal, fracIncr int) error {
 		}
 		if mod == nil {
 			to.wordBuf[idxTo] = int32(guess)
+		} else if quo != nil && idxTo < wordBufLen {
+			quo.wordBuf[idxTo] = int32(guess)
 		}
 		dcarry = tmp1[start1]
 		start1++
 	}
+	if quo != nil {
+		if wordsIntTo > wordBufLen {
+			maxDecimal(wordBufLen*digitsPerWord, 0, quo)
+			quoErr = ErrOverflow
+		} else if idx, digitsInt := quo.removeLeadingZeros(); idx != 0 {
+			copy(quo.wordBuf[:], quo.wordBuf[idx:])
+			quo.digitsInt = int8(digitsInt)
+		}
+		quo.negative = from1.negative != from2.negative && !quo.IsZero()
+	}
 	if mod != nil {
 		/*
 		   now the result is in tmp1, it has
@@ -2204,7 +2808,7 @@ func doDivMod(from1, from2, to, mod *Decimal, fracIncr int) error {
 		}
 
 		wordsFracTo = digitsToWords(int(to.digitsFrac))
-		err = nil
+		err = quoErr
 		if wordsIntTo == 0 && wordsFracTo == 0 {
 			*to = zeroBigDecimal
 			return err
@@ -2271,6 +2875,13 @@ func NewDecFromUint(i uint64) *Decimal {
 	return new(Decimal).FromUint(i)
 }
 
+// NewDecFromUnscaledBigInt creates a Decimal from unscaled value and scale, see FromUnscaledBigInt.
+func NewDecFromUnscaledBigInt(unscaled *big.Int, scale int32) (*Decimal, error) {
+	dec := new(Decimal)
+	err := dec.FromUnscaledBigInt(unscaled, scale)
+	return dec, err
+}
+
 // NewDecFromFloatForTest creates a Decimal from float, as it returns no error, it should only be used in test.
 func NewDecFromFloatForTest(f float64) *Decimal {
 	dec := new(Decimal)
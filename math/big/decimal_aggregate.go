@@ -0,0 +1,228 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxbig
+
+import (
+	"github.com/pkg/errors"
+)
+
+// The aggregations follow MySQL: the scale of SUM is the max scale of the values, and AVG keeps
+// DivFracIncr more digits than SUM and rounds half up. Like SQL NULL, the average, the min and the
+// max of no values are nil.
+
+// DecimalAccumulator aggregates a stream of decimals, the zero value is ready to use.
+// It is not safe for concurrent use.
+type DecimalAccumulator struct {
+	sum       Decimal
+	count     int64
+	min       *Decimal
+	max       *Decimal
+	err       error
+	truncated bool
+}
+
+// NewDecimalAccumulator returns an empty accumulator.
+func NewDecimalAccumulator() *DecimalAccumulator {
+	return &DecimalAccumulator{}
+}
+
+// Add accumulates d. Once the sum overflows, the error is returned by Add, Sum and Avg
+// until Reset, and the values added later are ignored. ErrTruncated is not fatal: the digits
+// after the point that don't fit are cut like DecimalAdd does, d is still accumulated, and
+// Sum and Avg return their results with ErrTruncated.
+func (a *DecimalAccumulator) Add(d *Decimal) error {
+	if a.err != nil {
+		return a.err
+	}
+	var sum Decimal
+	err := DecimalAdd(&a.sum, d, &sum)
+	if err != nil && err != ErrTruncated {
+		a.err = err
+		return err
+	}
+	a.truncated = a.truncated || err == ErrTruncated
+	a.sum = sum
+	a.count++
+	if a.min == nil || d.Compare(a.min) < 0 {
+		a.min = d.clone()
+	}
+	if a.max == nil || d.Compare(a.max) > 0 {
+		a.max = d.clone()
+	}
+	return err
+}
+
+// Reset empties the accumulator.
+func (a *DecimalAccumulator) Reset() {
+	*a = DecimalAccumulator{}
+}
+
+// Count returns the number of the accumulated values.
+func (a *DecimalAccumulator) Count() int64 {
+	return a.count
+}
+
+// Sum returns the sum of the accumulated values as MySQL SUM, its scale is the max scale of the
+// values, it is 0 if there is no value.
+func (a *DecimalAccumulator) Sum() (*Decimal, error) {
+	if a.err != nil {
+		return nil, a.err
+	}
+	return a.sum.clone(), a.truncatedErr(nil)
+}
+
+// SumRound returns the sum of the accumulated values rounded to scale digits after the point
+// with roundMode, scale can be negative.
+func (a *DecimalAccumulator) SumRound(scale int, roundMode RoundMode) (*Decimal, error) {
+	if a.err != nil {
+		return nil, a.err
+	}
+	to := new(Decimal)
+	err := a.sum.Round(to, scale, roundMode)
+	return to, a.truncatedErr(err)
+}
+
+// truncatedErr returns err, or ErrTruncated if err is nil and a value has been truncated.
+func (a *DecimalAccumulator) truncatedErr(err error) error {
+	if err == nil && a.truncated {
+		return ErrTruncated
+	}
+	return err
+}
+
+// Avg returns the average of the accumulated values as MySQL AVG, it has DivFracIncr more digits
+// after the point than the values and at most 30.
+func (a *DecimalAccumulator) Avg() (*Decimal, error) {
+	if a.err != nil || a.count == 0 {
+		return nil, a.err
+	}
+	to := new(Decimal)
+	if err := DecimalDiv(&a.sum, NewDecFromInt(a.count), to, DivFracIncr); err != nil {
+		return nil, err
+	}
+	frac := myMin(int(a.sum.GetDigitsFrac())+DivFracIncr, maxDecimalScale)
+	err := to.Round(to, frac, ModeHalfUp)
+	return to, a.truncatedErr(err)
+}
+
+// AvgRound returns the average of the accumulated values rounded to scale digits after the point
+// with roundMode, scale can be negative.
+func (a *DecimalAccumulator) AvgRound(scale int, roundMode RoundMode) (*Decimal, error) {
+	if a.err != nil || a.count == 0 {
+		return nil, a.err
+	}
+	to, err := a.sum.Div(NewDecFromInt(a.count), scale, roundMode)
+	return to, a.truncatedErr(err)
+}
+
+// Min returns the min of the accumulated values, or nil if there is no value.
+func (a *DecimalAccumulator) Min() *Decimal {
+	return a.min.clone()
+}
+
+// Max returns the max of the accumulated values, or nil if there is no value.
+func (a *DecimalAccumulator) Max() *Decimal {
+	return a.max.clone()
+}
+
+// clone returns a copy of d, or nil if d is nil.
+func (d *Decimal) clone() *Decimal {
+	if d == nil {
+		return nil
+	}
+	to := *d
+	return &to
+}
+
+// DecimalSum returns the sum of decs, it returns ErrOverflow if the sum overflows, and the
+// truncated sum with ErrTruncated if some digits after the point don't fit.
+func DecimalSum(decs []*Decimal) (*Decimal, error) {
+	a, err := accumulate(decs)
+	if err != nil {
+		return nil, err
+	}
+	return a.Sum()
+}
+
+// DecimalAvg returns the average of decs as MySQL AVG, or nil if decs is empty.
+func DecimalAvg(decs []*Decimal) (*Decimal, error) {
+	a, err := accumulate(decs)
+	if err != nil {
+		return nil, err
+	}
+	return a.Avg()
+}
+
+// accumulate adds decs to a new accumulator, it returns the fatal error of Add.
+func accumulate(decs []*Decimal) (*DecimalAccumulator, error) {
+	a := NewDecimalAccumulator()
+	for _, d := range decs {
+		if err := a.Add(d); err != nil && err != ErrTruncated {
+			return nil, err
+		}
+	}
+	return a, nil
+}
+
+// DecimalMin returns the min of decs, or nil if decs is empty.
+func DecimalMin(decs []*Decimal) *Decimal {
+	var min *Decimal
+	for _, d := range decs {
+		if min == nil || d.Compare(min) < 0 {
+			min = d
+		}
+	}
+	return min.clone()
+}
+
+// DecimalMax returns the max of decs, or nil if decs is empty.
+func DecimalMax(decs []*Decimal) *Decimal {
+	var max *Decimal
+	for _, d := range decs {
+		if max == nil || d.Compare(max) > 0 {
+			max = d
+		}
+	}
+	return max.clone()
+}
+
+// DecimalWeightedAvg returns sum(values[i] * weights[i]) / sum(weights) rounded to scale digits
+// after the point with roundMode, or nil if values is empty. It returns ErrDivByZero if the weights
+// sum to 0.
+func DecimalWeightedAvg(values, weights []*Decimal, scale int, roundMode RoundMode) (*Decimal, error) {
+	if len(values) != len(weights) {
+		return nil, errors.Errorf("%d values with %d weights", len(values), len(weights))
+	}
+	if len(values) == 0 {
+		return nil, nil
+	}
+	var sum, weightSum DecimalAccumulator
+	for i, v := range values {
+		var product Decimal
+		if err := DecimalMul(v, weights[i], &product); err != nil {
+			return nil, err
+		}
+		if err := sum.Add(&product); err != nil {
+			return nil, err
+		}
+		if err := weightSum.Add(weights[i]); err != nil {
+			return nil, err
+		}
+	}
+	return sum.sum.Div(&weightSum.sum, scale, roundMode)
+}
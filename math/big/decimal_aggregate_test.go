@@ -0,0 +1,158 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxbig
+
+import (
+	"strings"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func newDecsForTest(strs ...string) []*Decimal {
+	decs := make([]*Decimal, 0, len(strs))
+	for _, s := range strs {
+		decs = append(decs, NewDecFromStringForTest(s))
+	}
+	return decs
+}
+
+func TestDecimalAggregate(t *testing.T) {
+	decs := newDecsForTest("1.5", "-2.25", "10", "3.125")
+
+	sum, err := DecimalSum(decs)
+	assert.Nil(t, err)
+	assert.Equal(t, "12.375", string(sum.ToBytes()))
+	avg, err := DecimalAvg(decs)
+	assert.Nil(t, err)
+	assert.Equal(t, "3.0937500", string(avg.ToBytes()))
+	assert.Equal(t, "-2.25", string(DecimalMin(decs).ToBytes()))
+	assert.Equal(t, "10", string(DecimalMax(decs).ToBytes()))
+
+	avg, err = DecimalAvg(newDecsForTest("1", "1", "0"))
+	assert.Nil(t, err)
+	assert.Equal(t, "0.6667", string(avg.ToBytes()))
+
+	// the aggregations of no values.
+	sum, err = DecimalSum(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "0", string(sum.ToBytes()))
+	avg, err = DecimalAvg(nil)
+	assert.Nil(t, err)
+	assert.Nil(t, avg)
+	assert.Nil(t, DecimalMin(nil))
+	assert.Nil(t, DecimalMax(nil))
+
+	max := strings.Repeat("9", 81)
+	_, err = DecimalSum(newDecsForTest(max, "1"))
+	assert.Equal(t, ErrOverflow, err)
+	_, err = DecimalAvg(newDecsForTest(max, max))
+	assert.Equal(t, ErrOverflow, err)
+}
+
+func TestDecimalAccumulator(t *testing.T) {
+	acc := NewDecimalAccumulator()
+	for _, d := range newDecsForTest("2", "0.5", "-7") {
+		assert.Nil(t, acc.Add(d))
+	}
+	assert.Equal(t, int64(3), acc.Count())
+	sum, err := acc.Sum()
+	assert.Nil(t, err)
+	assert.Equal(t, "-4.5", string(sum.ToBytes()))
+	avg, err := acc.Avg()
+	assert.Nil(t, err)
+	assert.Equal(t, "-1.50000", string(avg.ToBytes()))
+	avg, err = acc.AvgRound(0, ModeHalfToEven)
+	assert.Nil(t, err)
+	assert.Equal(t, "-2", string(avg.ToBytes()))
+	assert.Equal(t, "-7", string(acc.Min().ToBytes()))
+	assert.Equal(t, "2", string(acc.Max().ToBytes()))
+
+	// the error sticks until Reset.
+	assert.Equal(t, ErrOverflow, acc.Add(NewDecFromStringForTest("-"+strings.Repeat("9", 81))))
+	assert.Equal(t, ErrOverflow, acc.Add(NewDecFromInt(1)))
+	_, err = acc.Sum()
+	assert.Equal(t, ErrOverflow, err)
+	_, err = acc.Avg()
+	assert.Equal(t, ErrOverflow, err)
+
+	acc.Reset()
+	assert.Equal(t, int64(0), acc.Count())
+	sum, err = acc.SumRound(2, ModeHalfUp)
+	assert.Nil(t, err)
+	assert.Equal(t, "0.00", string(sum.ToBytes()))
+
+	// the sum rounded to a scale.
+	for _, d := range newDecsForTest("1.255", "2.5", "-0.01") {
+		assert.Nil(t, acc.Add(d))
+	}
+	sum, err = acc.SumRound(2, ModeHalfUp)
+	assert.Nil(t, err)
+	assert.Equal(t, "3.75", string(sum.ToBytes()))
+	sum, err = acc.SumRound(-1, ModeUp)
+	assert.Nil(t, err)
+	assert.Equal(t, "10", string(sum.ToBytes()))
+	sum, err = acc.Sum()
+	assert.Nil(t, err)
+	assert.Equal(t, "3.745", string(sum.ToBytes()))
+
+	// ErrTruncated cuts the digits after the point that don't fit, but doesn't stick.
+	acc.Reset()
+	big := "1" + strings.Repeat("0", 79)
+	assert.Nil(t, acc.Add(NewDecFromStringForTest(big)))
+	assert.Equal(t, ErrTruncated, acc.Add(NewDecFromStringForTest("0.5")))
+	assert.Nil(t, acc.Add(NewDecFromInt(1)))
+	assert.Equal(t, int64(3), acc.Count())
+	sum, err = acc.Sum()
+	assert.Equal(t, ErrTruncated, err)
+	assert.Equal(t, "1"+strings.Repeat("0", 78)+"1", string(sum.ToBytes()))
+	_, err = acc.SumRound(0, ModeHalfUp)
+	assert.Equal(t, ErrTruncated, err)
+	assert.Equal(t, "0.5", string(acc.Min().ToBytes()))
+	sum, err = DecimalSum(newDecsForTest(big, "0.5", "1"))
+	assert.Equal(t, ErrTruncated, err)
+	assert.Equal(t, "1"+strings.Repeat("0", 78)+"1", string(sum.ToBytes()))
+
+	acc.Reset()
+	assert.Equal(t, int64(0), acc.Count())
+	assert.Nil(t, acc.Min())
+	avg, err = acc.AvgRound(2, ModeHalfUp)
+	assert.Nil(t, err)
+	assert.Nil(t, avg)
+}
+
+func TestDecimalWeightedAvg(t *testing.T) {
+	avg, err := DecimalWeightedAvg(newDecsForTest("1.5", "-2.25", "10", "3.125"),
+		newDecsForTest("1", "2", "0.5", "3"), 4, ModeHalfToEven)
+	assert.Nil(t, err)
+	assert.Equal(t, "1.7500", string(avg.ToBytes()))
+	// the average less than 1 rounded to a negative scale.
+	avg, err = DecimalWeightedAvg(newDecsForTest("0.5", "-0.25"), newDecsForTest("1", "1"), -2, ModeUp)
+	assert.Nil(t, err)
+	assert.Equal(t, "100", string(avg.ToBytes()))
+
+	_, err = DecimalWeightedAvg(newDecsForTest("1"), nil, 4, ModeHalfToEven)
+	assert.NotNil(t, err)
+	_, err = DecimalWeightedAvg(newDecsForTest("1", "2"), newDecsForTest("1", "-1"), 4, ModeHalfToEven)
+	assert.Equal(t, ErrDivByZero, err)
+	avg, err = DecimalWeightedAvg(nil, nil, 4, ModeHalfToEven)
+	assert.Nil(t, err)
+	assert.Nil(t, avg)
+}
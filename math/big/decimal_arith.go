@@ -0,0 +1,130 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxbig
+
+// The methods in this file are the immutable counterparts of DecimalAdd, DecimalSub, DecimalMul
+// and DecimalDiv. Neither the receiver nor the argument is ever modified, and the result is always
+// a newly allocated Decimal, so an operand can be passed as both, e.g. a.Add(a).
+//
+// Like the out-parameter functions, on ErrOverflow or ErrTruncated the result is still returned,
+// it is the max decimal or the truncated value respectively.
+
+// Add returns d + x.
+func (d *Decimal) Add(x *Decimal) (*Decimal, error) {
+	to := new(Decimal)
+	err := DecimalAdd(d, x, to)
+	return to, err
+}
+
+// Sub returns d - x.
+func (d *Decimal) Sub(x *Decimal) (*Decimal, error) {
+	to := new(Decimal)
+	err := DecimalSub(d, x, to)
+	return to, err
+}
+
+// Mul returns d * x.
+func (d *Decimal) Mul(x *Decimal) (*Decimal, error) {
+	to := new(Decimal)
+	err := DecimalMul(d, x, to)
+	return to, err
+}
+
+// Div returns d / x rounded to scale digits after the point with roundMode, scale can be negative.
+// Unlike DecimalDiv, the quotient is exactly rounded as java.math.BigDecimal.divide(x, scale, roundMode),
+// e.g. 1 / 3 with ModeUp to scale 2 is 0.34.
+func (d *Decimal) Div(x *Decimal, scale int, roundMode RoundMode) (*Decimal, error) {
+	// The quotient keeps at least 2 more digits than scale, the first one decides the rounding,
+	// and the last one is set if the division is inexact.
+	q := new(Decimal)
+	err := doDivMod(d, x, q, nil, myMax(scale+2, 0))
+	if err == ErrDivByZero || err == ErrOverflow {
+		return q, err
+	}
+	inexact := !d.IsZero() && (q.IsZero() || !isExactQuotient(d, x, q))
+	if inexact && int(q.digitsFrac) >= scale+2 {
+		q.negative = d.negative != x.negative
+		lastIdx := digitsToWords(int(q.digitsInt)) + digitsToWords(int(q.digitsFrac)) - 1
+		if lastIdx < 0 {
+			// the quotient truncated to a negative scale has no words, |d / x| < 1 then,
+			// which rounds the same as the sticky 0.1.
+			q.digitsFrac, q.resultFrac = 1, 1
+			q.wordBuf[0] = digMask
+		} else if q.wordBuf[lastIdx]%10 == 0 {
+			q.wordBuf[lastIdx]++
+		}
+	}
+
+	to := new(Decimal)
+	if roundErr := q.Round(to, scale, roundMode); roundErr != nil {
+		return to, roundErr
+	}
+	return to, err
+}
+
+// DivMod returns the integral quotient and the remainder of d / x rounded with mode, see DecimalDivModWithMode.
+func (d *Decimal) DivMod(x *Decimal, mode DivisionMode) (quo, rem *Decimal, err error) {
+	quo, rem = new(Decimal), new(Decimal)
+	err = DecimalDivModWithMode(d, x, quo, rem, mode)
+	return quo, rem, err
+}
+
+// DivideToIntegralValue returns the integral part of d / x, the same as java.math.BigDecimal.divideToIntegralValue.
+func (d *Decimal) DivideToIntegralValue(x *Decimal) (*Decimal, error) {
+	quo, _, err := d.DivMod(x, DivisionTruncated)
+	return quo, err
+}
+
+// Remainder returns d - d.DivideToIntegralValue(x) * x, the same as java.math.BigDecimal.remainder.
+func (d *Decimal) Remainder(x *Decimal) (*Decimal, error) {
+	_, rem, err := d.DivMod(x, DivisionTruncated)
+	return rem, err
+}
+
+// isExactQuotient checks whether q * divisor == dividend.
+func isExactQuotient(dividend, divisor, q *Decimal) bool {
+	v1, scale1 := dividend.ToUnscaledBigInt()
+	v2, scale2 := divisor.ToUnscaledBigInt()
+	vq, scaleq := q.ToUnscaledBigInt()
+	product := vq.Mul(vq, v2)
+	// align the scales of product and dividend.
+	if diff := int(scaleq+scale2) - int(scale1); diff > 0 {
+		v1.Mul(v1, bigPow10(diff))
+	} else if diff < 0 {
+		product.Mul(product, bigPow10(-diff))
+	}
+	return product.Cmp(v1) == 0
+}
+
+// Neg returns -d.
+func (d *Decimal) Neg() *Decimal {
+	return DecimalNeg(d)
+}
+
+// Abs returns |d|.
+func (d *Decimal) Abs() *Decimal {
+	to := *d
+	to.negative = false
+	to.updateJavaValue()
+	return &to
+}
+
+// Cmp compares d and x, returns -1/0/1, it is the same as Compare.
+func (d *Decimal) Cmp(x *Decimal) int {
+	return d.Compare(x)
+}
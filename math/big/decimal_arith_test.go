@@ -0,0 +1,135 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxbig
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImmutableArithmetic(t *testing.T) {
+	a := NewDecFromStringForTest("123.45")
+	b := NewDecFromStringForTest("-0.5")
+
+	sum, err := a.Add(b)
+	assert.Nil(t, err)
+	assert.Equal(t, "122.95", sum.String())
+	diff, err := a.Sub(b)
+	assert.Nil(t, err)
+	assert.Equal(t, "123.95", diff.String())
+	product, err := a.Mul(b)
+	assert.Nil(t, err)
+	assert.Equal(t, "-61.725", product.String())
+	assert.Equal(t, "-123.45", a.Neg().String())
+	assert.Equal(t, "0.5", b.Abs().String())
+	assert.Equal(t, "0.5", b.Abs().JavaValue)
+	assert.Equal(t, 1, a.Cmp(b))
+	assert.Equal(t, -1, b.Cmp(a))
+
+	// the operands are never modified, even when aliased.
+	double, err := a.Add(a)
+	assert.Nil(t, err)
+	assert.Equal(t, "246.90", double.String())
+	square, err := a.Mul(a)
+	assert.Nil(t, err)
+	assert.Equal(t, "15239.9025", square.String())
+	assert.Equal(t, "123.45", a.String())
+	assert.Equal(t, "-0.5", b.String())
+
+	_, err = NewMaxOrMinDec(false, 81, 0).Add(NewDecFromInt(1))
+	assert.Equal(t, ErrOverflow, err)
+}
+
+func TestImmutableDiv(t *testing.T) {
+	tests := []struct {
+		a, b   string
+		scale  int
+		mode   RoundMode
+		output string
+		err    error
+	}{
+		{"1", "3", 2, ModeHalfUp, "0.33", nil},
+		{"1", "3", 2, ModeUp, "0.34", nil},
+		{"-1", "3", 2, ModeFloor, "-0.34", nil},
+		{"-1", "3", 2, ModeCeiling, "-0.33", nil},
+		{"2", "3", 4, ModeDown, "0.6666", nil},
+		{"2", "3", 4, ModeHalfToEven, "0.6667", nil},
+		{"1", "8", 2, ModeHalfToEven, "0.12", nil},
+		{"3", "8", 2, ModeHalfToEven, "0.38", nil},
+		{"1", "8", 2, ModeHalfDown, "0.12", nil},
+		{"1.0000000001", "8", 2, ModeHalfDown, "0.13", nil},
+		{"1", "8", 3, ModeUnnecessary, "0.125", nil},
+		{"1", "3", 3, ModeUnnecessary, "", ErrRoundingNecessary},
+		{"1", "1000000000000", 2, ModeUp, "0.01", nil},
+		{"-1", "1000000000000", 2, ModeHalfUp, "0.00", nil},
+		{"12345", "1.23", 0, ModeHalfUp, "10037", nil},
+		{"12345", "1.23", -2, ModeHalfUp, "10000", nil},
+		{"123456789.987654321", "-1", 5, ModeHalfToEven, "-123456789.98765", nil},
+		{"0", "3", 2, ModeUp, "0.00", nil},
+		{"1", "3", -2, ModeUp, "100", nil},
+		{"1", "3", -2, ModeDown, "0", nil},
+		{"1", "1000", -2, ModeUp, "100", nil},
+		{"-5", "1000", -2, ModeUp, "-100", nil},
+		{"-5", "1000", -2, ModeHalfUp, "0", nil},
+		{"-5", "1000", -3, ModeFloor, "-1000", nil},
+		{"-1", "3", -2, ModeCeiling, "0", nil},
+		{"1", "1000", -1, ModeUp, "10", nil},
+		{"1", "3", -2, ModeUnnecessary, "", ErrRoundingNecessary},
+		{"1", "0", 2, ModeUp, "", ErrDivByZero},
+	}
+	for _, ca := range tests {
+		a := NewDecFromStringForTest(ca.a)
+		b := NewDecFromStringForTest(ca.b)
+		q, err := a.Div(b, ca.scale, ca.mode)
+		assert.Equal(t, ca.err, err, ca.a+"/"+ca.b)
+		if err == nil {
+			assert.Equal(t, ca.output, string(q.ToBytes()), ca.a+"/"+ca.b)
+		}
+		assert.Equal(t, ca.a, string(a.ToBytes()))
+	}
+}
+
+func TestImmutableDivMod(t *testing.T) {
+	a := NewDecFromStringForTest("-7.50")
+	b := NewDecFromStringForTest("2")
+
+	quo, err := a.DivideToIntegralValue(b)
+	assert.Nil(t, err)
+	assert.Equal(t, "-3.00", quo.String())
+	rem, err := a.Remainder(b)
+	assert.Nil(t, err)
+	assert.Equal(t, "-1.50", rem.String())
+
+	quo, rem, err = a.DivMod(b, DivisionEuclidean)
+	assert.Nil(t, err)
+	assert.Equal(t, "-4.00", quo.String())
+	assert.Equal(t, "0.50", rem.String())
+
+	// the operands are never modified, even when aliased.
+	quo, rem, err = a.DivMod(a, DivisionFloored)
+	assert.Nil(t, err)
+	assert.Equal(t, "1", quo.String())
+	assert.Equal(t, "0.00", rem.String())
+	assert.Equal(t, "-7.50", a.String())
+
+	_, _, err = a.DivMod(NewDecFromInt(0), DivisionTruncated)
+	assert.Equal(t, ErrDivByZero, err)
+}
@@ -57,7 +57,62 @@ func BenchmarkRound(b *testing.B) {
 			tests[i].inputDec.Round(&roundTo, tests[i].scale, ModeTruncate)
 		}
 		for i := 0; i < len(tests); i++ {
-			tests[i].inputDec.Round(&roundTo, tests[i].scale, modeCeiling)
+			tests[i].inputDec.Round(&roundTo, tests[i].scale, ModeCeiling)
 		}
 	}
 }
+
+// The small operands fit into the compact form, and the large ones take the words.
+var benchmarkArithOperands = []struct {
+	name string
+	a, b string
+}{
+	{name: "Small", a: "12345.67", b: "-89.125"},
+	{name: "Large", a: "1234567890123456789012345.67", b: "-8901234567890123456789.125"},
+}
+
+func benchmarkArith(b *testing.B, op func(from1, from2, to *Decimal) error) {
+	for _, operands := range benchmarkArithOperands {
+		var from1, from2, to Decimal
+		from1.FromString(operands.a)
+		from2.FromString(operands.b)
+		b.Run(operands.name, func(b *testing.B) {
+			for n := 0; n < b.N; n++ {
+				op(&from1, &from2, &to)
+			}
+		})
+	}
+}
+
+// syncJavaValue returns op updating the JavaValue of the result like the exported functions,
+// so that the word form is compared with them on the same terms.
+func syncJavaValue(op func(from1, from2, to *Decimal) error) func(from1, from2, to *Decimal) error {
+	return func(from1, from2, to *Decimal) error {
+		defer to.updateJavaValue()
+		return op(from1, from2, to)
+	}
+}
+
+// BenchmarkDecimalAdd runs addWords as well, which is DecimalAdd without the compact form.
+func BenchmarkDecimalAdd(b *testing.B) {
+	benchmarkArith(b, DecimalAdd)
+	b.Run("Words", func(b *testing.B) {
+		benchmarkArith(b, syncJavaValue(addWords))
+	})
+}
+
+// BenchmarkDecimalSub runs subWords as well, which is DecimalSub without the compact form.
+func BenchmarkDecimalSub(b *testing.B) {
+	benchmarkArith(b, DecimalSub)
+	b.Run("Words", func(b *testing.B) {
+		benchmarkArith(b, syncJavaValue(subWords))
+	})
+}
+
+// BenchmarkDecimalMul runs mulWords as well, which is DecimalMul without the compact form.
+func BenchmarkDecimalMul(b *testing.B) {
+	benchmarkArith(b, DecimalMul)
+	b.Run("Words", func(b *testing.B) {
+		benchmarkArith(b, syncJavaValue(mulWords))
+	})
+}
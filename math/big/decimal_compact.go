@@ -0,0 +1,172 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxbig
+
+// The compact form of a decimal is an int64 mantissa with a scale of whole words, when the decimal has at most
+// 2 words besides the leading zero ones, e.g. 12345.67 is the mantissa 12345670000000 with 1 fraction word.
+// Most of the decimals in practice, e.g. prices and amounts, have less than 10 digits before and after the
+// point. DecimalAdd, DecimalSub and DecimalMul compute them with the machine integers instead of the loops over
+// the word buffer, and promote them to the word form if an operand or the result does not fit. As the scale is
+// whole words, the conversions from and to the word buffer only multiply and divide by the constant wordBase.
+// The result is the same as the word form's, except that the leading zero words are dropped. The zero results
+// are left to the word form, which decides their scale. See BenchmarkDecimalAdd for the speedup.
+
+// compactLimit is the bound of the compact mantissas, wordBase^2. The sum of two mantissas below it fits into
+// an int64, and so do the products of their words.
+const compactLimit = wordBase * wordBase
+
+// compact returns the mantissa m and the number of the fraction words of d, d = m * wordBase^-wordsFrac.
+// ok is false if d has more than 2 words besides the leading zero ones, i.e. |m| >= compactLimit.
+func (d *Decimal) compact() (m int64, wordsFrac int, ok bool) {
+	wordsInt := digitsToWords(int(d.digitsInt))
+	wordsFrac = digitsToWords(int(d.digitsFrac))
+	i := 0
+	for i < wordsInt && d.wordBuf[i] == 0 {
+		i++
+	}
+	switch wordsInt + wordsFrac - i {
+	case 0:
+	case 1:
+		m = int64(d.wordBuf[i])
+	case 2:
+		m = int64(d.wordBuf[i])*wordBase + int64(d.wordBuf[i+1])
+	default:
+		return 0, 0, false
+	}
+	if d.negative {
+		m = -m
+	}
+	return m, wordsFrac, true
+}
+
+// alignCompact returns the mantissa m with f fraction words scaled to wordsFrac fraction words,
+// ok is false if it is not below compactLimit.
+func alignCompact(m int64, f, wordsFrac int) (int64, bool) {
+	for ; f < wordsFrac; f++ {
+		if m >= wordBase || m <= -wordBase {
+			return 0, false
+		}
+		m *= wordBase
+	}
+	return m, true
+}
+
+// setCompact sets d to the words, the lowest one first, with wordsFrac fraction words and digitsFrac
+// digits after the point, the fraction digits beyond digitsFrac must be zero. It returns false without
+// changing d if the value is zero or digitsFrac is too large.
+func (d *Decimal) setCompact(negative bool, words *[4]int32, wordsFrac, digitsFrac int) bool {
+	if digitsFrac > notFixedDec {
+		return false
+	}
+	n := len(words)
+	for n > 0 && words[n-1] == 0 {
+		n--
+	}
+	if n == 0 {
+		return false
+	}
+	n = myMax(n, wordsFrac)
+	// the trailing zero words beyond digitsFrac are dropped.
+	drop := wordsFrac - digitsToWords(digitsFrac)
+	d.wordBuf = [maxWordBufLen]int32{}
+	for i := 0; i < n-drop; i++ {
+		d.wordBuf[i] = words[n-1-i]
+	}
+	d.digitsInt = int8((n - wordsFrac) * digitsPerWord)
+	d.digitsFrac = int8(digitsFrac)
+	d.negative = negative
+	return true
+}
+
+// addCompact sets to = from1 + from2, or from1 - from2 if sub is true, in the compact form. It returns false
+// without changing to if an operand or the aligned operands do not fit, or the result is zero.
+func addCompact(from1, from2, to *Decimal, sub bool) bool {
+	m1, f1, ok := from1.compact()
+	if !ok {
+		return false
+	}
+	m2, f2, ok := from2.compact()
+	if !ok {
+		return false
+	}
+	if sub {
+		m2 = -m2
+	}
+	wordsFrac := myMax(f1, f2)
+	if m1, ok = alignCompact(m1, f1, wordsFrac); !ok {
+		return false
+	}
+	if m2, ok = alignCompact(m2, f2, wordsFrac); !ok {
+		return false
+	}
+	// |m1 + m2| < 2 * compactLimit, which has 3 words.
+	x := m1 + m2
+	negative := x < 0
+	if negative {
+		x = -x
+	}
+	words := [4]int32{int32(x % wordBase), int32(x / wordBase % wordBase), int32(x / compactLimit)}
+
+	digitsFrac := myMax(int(from1.digitsFrac), int(from2.digitsFrac))
+	resultFrac := myMaxInt8(from1.resultFrac, from2.resultFrac)
+	if !to.setCompact(negative, &words, wordsFrac, digitsFrac) {
+		return false
+	}
+	to.resultFrac = resultFrac
+	return true
+}
+
+// mulCompact sets to = from1 * from2 in the compact form. It returns false without changing to
+// if an operand does not fit.
+func mulCompact(from1, from2, to *Decimal) bool {
+	m1, f1, ok := from1.compact()
+	if !ok {
+		return false
+	}
+	m2, f2, ok := from2.compact()
+	if !ok {
+		return false
+	}
+	negative := (m1 < 0) != (m2 < 0)
+	hi1, lo1 := splitCompact(m1)
+	hi2, lo2 := splitCompact(m2)
+	// the partial products are below compactLimit, and their sums with the carries never overflow.
+	var words [4]int32
+	x := lo1 * lo2
+	words[0] = int32(x % wordBase)
+	x = hi1*lo2 + lo1*hi2 + x/wordBase
+	words[1] = int32(x % wordBase)
+	x = hi1*hi2 + x/wordBase
+	words[2], words[3] = int32(x%wordBase), int32(x/wordBase)
+
+	digitsFrac := int(from1.digitsFrac) + int(from2.digitsFrac)
+	resultFrac := myMinInt8(from1.resultFrac+from2.resultFrac, maxDecimalScale)
+	if !to.setCompact(negative, &words, f1+f2, digitsFrac) {
+		return false
+	}
+	to.resultFrac = resultFrac
+	return true
+}
+
+// splitCompact returns the 2 words of the absolute value of the mantissa m.
+func splitCompact(m int64) (hi, lo uint64) {
+	if m < 0 {
+		m = -m
+	}
+	return uint64(m / wordBase), uint64(m % wordBase)
+}
@@ -0,0 +1,157 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxbig
+
+import (
+	"math/big"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompact(t *testing.T) {
+	tests := []struct {
+		input     string
+		m         int64
+		wordsFrac int
+		ok        bool
+	}{
+		{"0", 0, 0, true},
+		{"-12.345", -12345000000, 1, true},
+		{"0.123456789", 123456789, 1, true},
+		{"123456789.987654321", 123456789987654321, 1, true},
+		{"0.000000000000001", 1000, 2, true},
+		{"999999999999999999", 999999999999999999, 0, true},
+		{"-999999999999999999", -999999999999999999, 0, true},
+		{"1000000000000000000", 0, 0, false},
+		{"1234567890.12345678", 0, 0, false},
+		{"0.000000000000000000000000000001", 0, 0, false},
+	}
+	for _, tt := range tests {
+		var d Decimal
+		assert.NoError(t, d.FromString(tt.input))
+		m, wordsFrac, ok := d.compact()
+		assert.Equal(t, tt.ok, ok, tt.input)
+		if ok {
+			assert.Equal(t, tt.m, m, tt.input)
+			assert.Equal(t, tt.wordsFrac, wordsFrac, tt.input)
+		}
+	}
+
+	// the leading zero words of NewDecFromInt do not count.
+	d := NewDecFromInt(1000000000)
+	m, wordsFrac, ok := d.compact()
+	assert.True(t, ok)
+	assert.Equal(t, int64(1000000000), m)
+	assert.Equal(t, 0, wordsFrac)
+
+	// the operands aligned beyond 2 words take the word form.
+	a, b := NewDecFromInt(123456789012), NewDecFromStringForTest("0.5")
+	var sum Decimal
+	assert.False(t, addCompact(a, b, &sum, false))
+	assert.NoError(t, DecimalAdd(a, b, &sum))
+	assert.Equal(t, "123456789012.5", sum.String())
+}
+
+func TestCompactArith(t *testing.T) {
+	tests := []struct {
+		a, b           string
+		sum, diff, mul string
+	}{
+		{"1.5", "2.25", "3.75", "-0.75", "3.375"},
+		{"-1.5", "1.5", "0", "-3.0", "-2.25"},
+		{"0.1", "-0.25", "-0.15", "0.35", "-0.025"},
+		{"999999999999999999", "1", "1000000000000000000", "999999999999999998", "999999999999999999"},
+		{"999999999999999999", "999999999999999999", "1999999999999999998", "0", "999999999999999998000000000000000001"},
+		{"123456789.123456789", "-0.000000001", "123456789.123456788", "123456789.123456790", "-0.123456789123456789"},
+		{"0.000000000000001", "0.000000000000001", "0.000000000000002", "0", "0.000000000000000000000000000001"},
+	}
+	for _, tt := range tests {
+		a, b := new(Decimal), new(Decimal)
+		assert.NoError(t, a.FromString(tt.a))
+		assert.NoError(t, b.FromString(tt.b))
+		var sum, diff, mul Decimal
+		assert.NoError(t, DecimalAdd(a, b, &sum))
+		assert.NoError(t, DecimalSub(a, b, &diff))
+		assert.NoError(t, DecimalMul(a, b, &mul))
+		assert.Equal(t, tt.sum, sum.String(), "%s + %s", tt.a, tt.b)
+		assert.Equal(t, tt.diff, diff.String(), "%s - %s", tt.a, tt.b)
+		assert.Equal(t, tt.mul, mul.String(), "%s * %s", tt.a, tt.b)
+	}
+}
+
+// TestCompactRandom checks the results against big.Int and the word form, both the small
+// and the large operands.
+func TestCompactRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	randDecimal := func() (*Decimal, *big.Int, int) {
+		var b strings.Builder
+		if r.Intn(2) == 0 {
+			b.WriteByte('-')
+		}
+		digits := 1 + r.Intn(24)
+		for i := 0; i < digits; i++ {
+			b.WriteByte(byte('0' + r.Intn(10)))
+		}
+		if scale := r.Intn(myMin(digits, 15) + 1); scale > 0 && scale < digits {
+			str := b.String()
+			b.Reset()
+			b.WriteString(str[:len(str)-scale] + "." + str[len(str)-scale:])
+		}
+		d := new(Decimal)
+		assert.NoError(t, d.FromString(b.String()))
+		v, scale := d.ToUnscaledBigInt()
+		return d, v, int(scale)
+	}
+	check := func(op string, to *Decimal, v *big.Int, scale int) {
+		got, gotScale := to.ToUnscaledBigInt()
+		// the zero results are left to the word form, which drops the scale.
+		if v.Sign() != 0 {
+			assert.Equal(t, scale, int(gotScale), op)
+		}
+		assert.Equal(t, v.String(), got.String(), op)
+		assert.Equal(t, string(to.ToBytes()), to.JavaValue, op)
+	}
+
+	for i := 0; i < 2000; i++ {
+		a, v1, s1 := randDecimal()
+		b, v2, s2 := randDecimal()
+		op := a.String() + ", " + b.String()
+
+		scale := myMax(s1, s2)
+		x1 := new(big.Int).Mul(v1, bigPow10(scale-s1))
+		x2 := new(big.Int).Mul(v2, bigPow10(scale-s2))
+		var sum, diff, mul, words Decimal
+		assert.NoError(t, DecimalAdd(a, b, &sum))
+		check("add "+op, &sum, new(big.Int).Add(x1, x2), scale)
+		assert.NoError(t, DecimalSub(a, b, &diff))
+		check("sub "+op, &diff, new(big.Int).Sub(x1, x2), scale)
+		assert.NoError(t, DecimalMul(a, b, &mul))
+		assert.NoError(t, mulWords(a, b, &words))
+		check("mul "+op, &mul, new(big.Int).Mul(v1, v2), s1+s2)
+		assert.Equal(t, words.String(), mul.String(), "mul "+op)
+		assert.NoError(t, addWords(a, b, &words))
+		assert.Equal(t, words.String(), sum.String(), "add "+op)
+		assert.NoError(t, subWords(a, b, &words))
+		assert.Equal(t, words.String(), diff.String(), "sub "+op)
+	}
+}
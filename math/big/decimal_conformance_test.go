@@ -0,0 +1,270 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxbig
+
+import (
+	"flag"
+	"math/big"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+// The conformance tests compare the results of Decimal with math/big. Run millions of operands by
+//
+//	go test -run TestConformance -conformance.rounds 1000000 -conformance.seed 0
+var (
+	conformanceRounds = flag.Int("conformance.rounds", 20000, "the number of the random operands of the conformance tests")
+	conformanceSeed   = flag.Int64("conformance.seed", 1, "the seed of the random operands, 0 is the current time")
+)
+
+// conformanceRand returns the random source of a conformance test, the seed is logged to reproduce a failure.
+func conformanceRand(t *testing.T) (*rand.Rand, int) {
+	seed := *conformanceSeed
+	if seed == 0 {
+		seed = rand.Int63()
+	}
+	t.Logf("seed %d", seed)
+	rounds := *conformanceRounds
+	if testing.Short() {
+		rounds /= 10
+	}
+	return rand.New(rand.NewSource(seed)), rounds
+}
+
+// randDecimalString returns a decimal string of at most maxInt digits before the point and maxFrac after it,
+// the digits are skewed to zeros and nines to reach the carries and the word boundaries.
+func randDecimalString(r *rand.Rand, maxInt, maxFrac int) string {
+	var b strings.Builder
+	if r.Intn(2) == 0 {
+		b.WriteByte('-')
+	}
+	digit := func() byte {
+		switch r.Intn(4) {
+		case 0:
+			return '0'
+		case 1:
+			return '9'
+		}
+		return byte('0' + r.Intn(10))
+	}
+	digitsInt := r.Intn(maxInt + 1)
+	for i := 0; i < digitsInt; i++ {
+		b.WriteByte(digit())
+	}
+	if digitsInt == 0 {
+		b.WriteByte('0')
+	}
+	if digitsFrac := r.Intn(maxFrac + 1); digitsFrac > 0 {
+		b.WriteByte('.')
+		for i := 0; i < digitsFrac; i++ {
+			b.WriteByte(digit())
+		}
+	}
+	return b.String()
+}
+
+// decimalToRat returns the exact value of d.
+func decimalToRat(d *Decimal) *big.Rat {
+	v, scale := d.ToUnscaledBigInt()
+	return new(big.Rat).SetFrac(v, bigPow10(int(scale)))
+}
+
+// ratRound rounds r to frac digits after the point with roundMode, frac can be negative.
+func ratRound(r *big.Rat, frac int, roundMode RoundMode) *big.Rat {
+	num, denom := new(big.Int).Set(r.Num()), new(big.Int).Set(r.Denom())
+	if frac >= 0 {
+		num.Mul(num, bigPow10(frac))
+	} else {
+		denom.Mul(denom, bigPow10(-frac))
+	}
+	q, m := new(big.Int).QuoRem(num, denom, new(big.Int))
+	if m.Sign() != 0 {
+		half := new(big.Int).Abs(m)
+		cmpHalf := half.Lsh(half, 1).Cmp(denom)
+		var up bool
+		switch roundMode {
+		case ModeCeiling:
+			up = m.Sign() > 0
+		case ModeFloor:
+			up = m.Sign() < 0
+		case ModeUp:
+			up = true
+		case ModeHalfUp, ModeHalfEven:
+			up = cmpHalf >= 0
+		case ModeHalfDown:
+			up = cmpHalf > 0
+		case ModeHalfToEven:
+			up = cmpHalf > 0 || cmpHalf == 0 && q.Bit(0) == 1
+		}
+		if up {
+			q.Add(q, big.NewInt(int64(m.Sign())))
+		}
+	}
+	if frac >= 0 {
+		return new(big.Rat).SetFrac(q, bigPow10(frac))
+	}
+	return new(big.Rat).SetInt(q.Mul(q, bigPow10(-frac)))
+}
+
+func TestConformanceArith(t *testing.T) {
+	r, rounds := conformanceRand(t)
+	for i := 0; i < rounds; i++ {
+		s1, s2 := randDecimalString(r, 36, 15), randDecimalString(r, 36, 15)
+		a, b := NewDecFromStringForTest(s1), NewDecFromStringForTest(s2)
+		x, y := decimalToRat(a), decimalToRat(b)
+		_, scale1 := a.ToUnscaledBigInt()
+		_, scale2 := b.ToUnscaledBigInt()
+
+		var sum, diff, product Decimal
+		if assert.NoError(t, DecimalAdd(a, b, &sum), "%s + %s", s1, s2) {
+			assert.Equal(t, new(big.Rat).Add(x, y).RatString(), decimalToRat(&sum).RatString(), "%s + %s", s1, s2)
+			assert.Equal(t, myMaxInt8(int8(scale1), int8(scale2)), sum.GetDigitsFrac(), "%s + %s", s1, s2)
+		}
+		if assert.NoError(t, DecimalSub(a, b, &diff), "%s - %s", s1, s2) {
+			assert.Equal(t, new(big.Rat).Sub(x, y).RatString(), decimalToRat(&diff).RatString(), "%s - %s", s1, s2)
+		}
+		// the product of many digits is truncated to the word buffer.
+		switch err := DecimalMul(a, b, &product); err {
+		case nil:
+			assert.Equal(t, new(big.Rat).Mul(x, y).RatString(), decimalToRat(&product).RatString(), "%s * %s", s1, s2)
+		case ErrTruncated:
+			want := ratRound(new(big.Rat).Mul(x, y), int(product.GetDigitsFrac()), ModeTruncate)
+			assert.Equal(t, want.RatString(), decimalToRat(&product).RatString(), "%s * %s", s1, s2)
+		default:
+			assert.Equal(t, ErrOverflow, err, "%s * %s", s1, s2)
+		}
+
+		if b.IsZero() {
+			var quo Decimal
+			assert.Equal(t, ErrDivByZero, DecimalDiv(a, b, &quo, DivFracIncr), "%s / %s", s1, s2)
+			continue
+		}
+		// the quotient is truncated to the digits after the point.
+		var quo, mod Decimal
+		if err := DecimalDiv(a, b, &quo, DivFracIncr); err == nil {
+			exact := new(big.Rat).Quo(x, y)
+			want := ratRound(exact, int(quo.GetDigitsFrac()), ModeTruncate)
+			assert.Equal(t, want.RatString(), decimalToRat(&quo).RatString(), "%s / %s", s1, s2)
+			assert.True(t, a.IsZero() || int(quo.GetDigitsFrac()) >= int(scale1)+DivFracIncr, "%s / %s", s1, s2)
+		} else {
+			assert.Equal(t, ErrTruncated, err, "%s / %s", s1, s2)
+		}
+		// the remainder has the sign of the dividend.
+		if assert.NoError(t, DecimalMod(a, b, &mod), "%s %% %s", s1, s2) {
+			q := ratRound(new(big.Rat).Quo(x, y), 0, ModeTruncate)
+			want := new(big.Rat).Sub(x, q.Mul(q, y))
+			assert.Equal(t, want.RatString(), decimalToRat(&mod).RatString(), "%s %% %s", s1, s2)
+		}
+		// the quotient rounds with the division mode and the remainder follows.
+		for mode, roundMode := range map[DivisionMode]RoundMode{
+			DivisionTruncated: ModeTruncate,
+			DivisionFloored:   ModeFloor,
+			DivisionEuclidean: ModeFloor,
+		} {
+			if mode == DivisionEuclidean && y.Sign() < 0 {
+				roundMode = ModeCeiling
+			}
+			var quo, rem Decimal
+			if assert.NoError(t, DecimalDivModWithMode(a, b, &quo, &rem, mode), "%s divmod %s", s1, s2) {
+				q := ratRound(new(big.Rat).Quo(x, y), 0, roundMode)
+				assert.Equal(t, q.RatString(), decimalToRat(&quo).RatString(), "%s divmod %s, %d", s1, s2, mode)
+				want := new(big.Rat).Sub(x, q.Mul(q, y))
+				assert.Equal(t, want.RatString(), decimalToRat(&rem).RatString(), "%s divmod %s, %d", s1, s2, mode)
+			}
+		}
+	}
+}
+
+func TestConformanceRound(t *testing.T) {
+	roundModes := []RoundMode{ModeCeiling, ModeUp, ModeFloor, ModeHalfEven, ModeHalfUp, ModeHalfDown, ModeHalfToEven, ModeTruncate}
+	r, rounds := conformanceRand(t)
+	for i := 0; i < rounds; i++ {
+		s := randDecimalString(r, 40, 30)
+		d := NewDecFromStringForTest(s)
+		x := decimalToRat(d)
+		frac := r.Intn(40) - 10
+		roundMode := roundModes[r.Intn(len(roundModes))]
+
+		var to Decimal
+		if err := d.Round(&to, frac, roundMode); err == nil {
+			assert.Equal(t, ratRound(x, frac, roundMode).RatString(), decimalToRat(&to).RatString(),
+				"round(%s, %d, %d)", s, frac, roundMode)
+		} else {
+			assert.Equal(t, ErrOverflow, err, "round(%s, %d, %d)", s, frac, roundMode)
+		}
+
+		// ModeUnnecessary succeeds only if the rounding is exact.
+		err := d.Round(&to, frac, ModeUnnecessary)
+		exact := ratRound(x, frac, ModeTruncate).Cmp(x) == 0
+		assert.Equal(t, exact, err != ErrRoundingNecessary, "round(%s, %d)", s, frac)
+	}
+}
+
+func TestConformanceShift(t *testing.T) {
+	r, rounds := conformanceRand(t)
+	for i := 0; i < rounds; i++ {
+		s := randDecimalString(r, 30, 20)
+		d := NewDecFromStringForTest(s)
+		x := decimalToRat(d)
+		shift := r.Intn(61) - 30
+		if err := d.Shift(shift); err != nil {
+			continue
+		}
+		factor := new(big.Rat).SetInt(bigPow10(myAbs(shift)))
+		if shift < 0 {
+			factor.Inv(factor)
+		}
+		assert.Equal(t, x.Mul(x, factor).RatString(), decimalToRat(d).RatString(), "shift(%s, %d)", s, shift)
+	}
+}
+
+func TestConformanceEncoding(t *testing.T) {
+	r, rounds := conformanceRand(t)
+	for i := 0; i < rounds; i++ {
+		s := randDecimalString(r, 50, 30)
+		d := NewDecFromStringForTest(s)
+
+		// the string round trip keeps the scale.
+		var str Decimal
+		assert.NoError(t, str.FromString(d.String()), s)
+		assert.Equal(t, d.String(), str.String(), s)
+		assert.Equal(t, 0, d.Compare(&str), s)
+
+		precision, frac := d.PrecisionAndFrac()
+		bin, err := d.ToBin(precision, frac)
+		if assert.NoError(t, err, s) {
+			var fromBin Decimal
+			binSize, err := fromBin.FromBin(bin, precision, frac)
+			assert.NoError(t, err, s)
+			assert.Equal(t, len(bin), binSize, s)
+			assert.Equal(t, decimalToRat(d).RatString(), decimalToRat(&fromBin).RatString(), s)
+		}
+	}
+}
+
+func myAbs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
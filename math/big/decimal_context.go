@@ -0,0 +1,511 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxbig
+
+import (
+	"math/big"
+	"strings"
+)
+
+// Condition is a set of the exceptional conditions of the General Decimal Arithmetic.
+type Condition uint8
+
+const (
+	// ConditionInexact is raised if non-zero digits are discarded by the rounding.
+	ConditionInexact Condition = 1 << iota
+	// ConditionOverflow is raised if the result does not fit into the word buffer.
+	ConditionOverflow
+	// ConditionDivisionByZero is raised if a non-zero number is divided by zero.
+	ConditionDivisionByZero
+	// ConditionInvalidOperation is raised by 0/0, the remainder by zero and the square root
+	// or the logarithm of a non-positive number.
+	ConditionInvalidOperation
+
+	// DefaultTraps are the conditions trapped by NewContext.
+	DefaultTraps = ConditionOverflow | ConditionDivisionByZero | ConditionInvalidOperation
+)
+
+var conditionNames = []string{"inexact", "overflow", "division by zero", "invalid operation"}
+
+// String returns the names of the conditions like "inexact|overflow".
+func (c Condition) String() string {
+	var names []string
+	for i, name := range conditionNames {
+		if c&(1<<uint(i)) != 0 {
+			names = append(names, name)
+		}
+	}
+	return strings.Join(names, "|")
+}
+
+// err returns the error of the most severe condition.
+func (c Condition) err() error {
+	switch {
+	case c&ConditionInvalidOperation != 0:
+		return ErrBadNumber
+	case c&ConditionDivisionByZero != 0:
+		return ErrDivByZero
+	case c&ConditionOverflow != 0:
+		return ErrOverflow
+	case c&ConditionInexact != 0:
+		return ErrTruncated
+	}
+	return nil
+}
+
+// Context is the environment of the arithmetic like java.math.MathContext, it fixes the precision,
+// the rounding and the conditions returned as errors, instead of the fracIncr, the scale and the round
+// mode arguments of the Decimal functions.
+//
+// The results are rounded to Precision significant digits and MaxScale digits after the point with
+// RoundMode. Every raised condition is added to Flags, and the conditions in Traps are returned as errors:
+// ErrTruncated for ConditionInexact, ErrOverflow, ErrDivByZero and ErrBadNumber for ConditionInvalidOperation.
+// With an error, the result is still returned like the Decimal functions if there is one, i.e. the max
+// decimal on overflow and nil for the division by zero and the invalid operations. RoundMode ModeUnnecessary
+// always returns ErrRoundingNecessary for an inexact result.
+//
+// The immutable methods of Decimal have the Context methods of the same names, e.g. Context.SetScale for
+// Decimal.SetScale, and Context.Sum and Context.Avg aggregate like DecimalSum and DecimalAvg. The out-parameter
+// functions and Shift take a Context as well, e.g. DecimalAddWithContext and ShiftWithContext, so a Context can be
+// threaded through the code written with them. A nil Context keeps the behavior of the functions without it.
+//
+// A Context is not safe for concurrent use as the operations update Flags.
+type Context struct {
+	// Precision is the max number of significant digits, 0 means the 81 digits of the word buffer.
+	Precision int
+	// MaxScale is the max number of digits after the point, it is 0 if negative.
+	MaxScale int
+	// RoundMode is the rounding of the inexact results.
+	RoundMode RoundMode
+	// Traps are the conditions returned as errors.
+	Traps Condition
+	// Flags are the conditions raised by the operations, reset it to 0 to clear them.
+	Flags Condition
+}
+
+// NewContext returns a Context trapping DefaultTraps.
+func NewContext(precision, maxScale int, roundMode RoundMode) *Context {
+	return &Context{
+		Precision: precision,
+		MaxScale:  maxScale,
+		RoundMode: roundMode,
+		Traps:     DefaultTraps,
+	}
+}
+
+// NewMySQLContext returns the Context of MySQL DECIMAL, which has at most 65 digits and 30 digits after the point.
+func NewMySQLContext() *Context {
+	return NewContext(65, maxDecimalScale, ModeHalfUp)
+}
+
+func (c *Context) precision() int {
+	if c.Precision <= 0 || c.Precision > wordBufLen*digitsPerWord {
+		return wordBufLen * digitsPerWord
+	}
+	return c.Precision
+}
+
+func (c *Context) maxScale() int {
+	return myMax(myMin(c.MaxScale, wordBufLen*digitsPerWord), 0)
+}
+
+// round is the rounder of the context.
+func (c *Context) round(u *UnboundedDecimal) error {
+	frac := myMin(u.digitsFrac, c.maxScale())
+	if u.IsZero() {
+		return u.Round(u, frac, c.RoundMode)
+	}
+	digits := len(new(big.Int).Abs(&u.value).Text(10))
+	frac = myMin(frac, c.precision()-digits+u.digitsFrac)
+	if err := u.Round(u, frac, c.RoundMode); err != nil {
+		return err
+	}
+	// the rounding may carry into a new digit, e.g. 9.99 to 10.00, drop the last zero then.
+	if len(new(big.Int).Abs(&u.value).Text(10)) > c.precision() && u.digitsFrac > 0 {
+		return u.Round(u, u.digitsFrac-1, c.RoundMode)
+	}
+	return nil
+}
+
+// result raises the conditions of an operation.
+func (c *Context) result(to *Decimal, inexact bool, err error) (*Decimal, error) {
+	var cond Condition
+	switch err {
+	case nil:
+	case ErrTruncated:
+		inexact = true
+	case ErrOverflow:
+		cond |= ConditionOverflow
+	case ErrDivByZero:
+		cond |= ConditionDivisionByZero
+	case ErrBadNumber:
+		cond |= ConditionInvalidOperation
+	default:
+		return to, err
+	}
+	if inexact {
+		cond |= ConditionInexact
+	}
+	c.Flags |= cond
+	return to, (cond & c.Traps).err()
+}
+
+// roundExact rounds the exact fixed-point value v with w digits.
+func (c *Context) roundExact(v *big.Int, w int) (*Decimal, error) {
+	return c.result(roundFixed(v, w, 0, c.round))
+}
+
+// Round rounds x to the context.
+func (c *Context) Round(x *Decimal) (*Decimal, error) {
+	v, w := x.ToUnscaledBigInt()
+	return c.roundExact(v, int(w))
+}
+
+// Neg returns -x.
+func (c *Context) Neg(x *Decimal) (*Decimal, error) {
+	v, w := x.ToUnscaledBigInt()
+	return c.roundExact(v.Neg(v), int(w))
+}
+
+// Abs returns |x|.
+func (c *Context) Abs(x *Decimal) (*Decimal, error) {
+	v, w := x.ToUnscaledBigInt()
+	return c.roundExact(v.Abs(v), int(w))
+}
+
+// Add returns x + y.
+func (c *Context) Add(x, y *Decimal) (*Decimal, error) {
+	v1, v2, w := alignUnboundedDecimals(NewUnboundedDecFromDecimal(x), NewUnboundedDecFromDecimal(y))
+	return c.roundExact(v1.Add(v1, v2), w)
+}
+
+// Sub returns x - y.
+func (c *Context) Sub(x, y *Decimal) (*Decimal, error) {
+	v1, v2, w := alignUnboundedDecimals(NewUnboundedDecFromDecimal(x), NewUnboundedDecFromDecimal(y))
+	return c.roundExact(v1.Sub(v1, v2), w)
+}
+
+// Mul returns x * y.
+func (c *Context) Mul(x, y *Decimal) (*Decimal, error) {
+	v1, w1 := x.ToUnscaledBigInt()
+	v2, w2 := y.ToUnscaledBigInt()
+	return c.roundExact(v1.Mul(v1, v2), int(w1+w2))
+}
+
+// Quo returns x / y.
+func (c *Context) Quo(x, y *Decimal) (*Decimal, error) {
+	v1, w1 := x.ToUnscaledBigInt()
+	v2, w2 := y.ToUnscaledBigInt()
+	return c.quo(v1, int(w1), v2, int(w2))
+}
+
+// quo returns v1 * 10^-w1 / (v2 * 10^-w2).
+func (c *Context) quo(v1 *big.Int, w1 int, v2 *big.Int, w2 int) (*Decimal, error) {
+	if v2.Sign() == 0 {
+		if v1.Sign() == 0 {
+			return c.result(nil, false, ErrBadNumber)
+		}
+		return c.result(nil, false, ErrDivByZero)
+	}
+	// the quotient is truncated to one more digit than MaxScale, and the remainder decides the sticky digit.
+	w := c.maxScale() + 1
+	if shift := w + w2 - w1; shift >= 0 {
+		v1.Mul(v1, bigPow10(shift))
+	} else {
+		v2.Mul(v2, bigPow10(-shift))
+	}
+	q, r := new(big.Int).QuoRem(v1, v2, new(big.Int))
+	sticky := 0
+	switch {
+	case r.Sign() == 0:
+		// an exact quotient prefers the scale w1 - w2.
+		q, w = stripFixedZeros(q, w, myMax(w1-w2, 0))
+	case (v1.Sign() < 0) != (v2.Sign() < 0):
+		sticky = -1
+	default:
+		sticky = 1
+	}
+	return c.result(roundFixed(q, w, sticky, c.round))
+}
+
+// Rem returns the remainder of x / y, it has the sign of x like DecimalMod.
+func (c *Context) Rem(x, y *Decimal) (*Decimal, error) {
+	if y.IsZero() {
+		return c.result(nil, false, ErrBadNumber)
+	}
+	var rem UnboundedDecimal
+	if err := UnboundedDecimalMod(NewUnboundedDecFromDecimal(x), NewUnboundedDecFromDecimal(y), &rem); err != nil {
+		return c.result(nil, false, err)
+	}
+	return c.roundExact(&rem.value, rem.digitsFrac)
+}
+
+// Pow returns x^n, n can be negative. 0^0 is 1 and 0^n is the division by zero for a negative n.
+func (c *Context) Pow(x *Decimal, n int) (*Decimal, error) {
+	if n < -maxPowExponent || n > maxPowExponent {
+		return c.result(nil, false, ErrBadNumber)
+	}
+	v, w := x.ToUnscaledBigInt()
+	if n == 0 || v.Sign() == 0 || powExact(v, n) {
+		if n >= 0 {
+			return c.roundExact(v.Exp(v, big.NewInt(int64(n)), nil), int(w)*n)
+		}
+		v.Exp(v, big.NewInt(int64(-n)), nil)
+		return c.quo(big.NewInt(1), 0, v, int(w)*-n)
+	}
+
+	// the size of the result is checked before the power is computed, like Decimal.Pow.
+	maxDigits := wordBufLen * digitsPerWord
+	negative := v.Sign() < 0 && n%2 != 0
+	sign := 1
+	if negative {
+		sign = -1
+	}
+	switch e := powLog10(v, int(w), n); {
+	case e > float64(maxDigits+1):
+		return c.roundExact(signedPow10(maxDigits+1, negative), 0)
+	case e < -float64(maxDigits+2):
+		return c.result(roundFixed(new(big.Int), maxDigits+1, sign, c.round))
+	}
+	// the digits after MaxScale decide the rounding together with the sticky digit.
+	p, inexact := powFixed(v, int(w), n, maxDigits+1)
+	if negative {
+		p.Neg(p)
+	}
+	if !inexact {
+		sign = 0
+	}
+	return c.result(roundFixed(p, maxDigits+1, sign, c.round))
+}
+
+// Div returns x / y, it is Quo under the name of Decimal.Div.
+func (c *Context) Div(x, y *Decimal) (*Decimal, error) {
+	return c.Quo(x, y)
+}
+
+// DivMod returns the integral quotient and the remainder of x / y rounded with mode like
+// DecimalDivModWithMode. The quotient prefers the scale max(scale of x - scale of y, 0), and the remainder
+// has the max scale of x and y. y is zero is the division by zero, or the invalid operation if x is zero too.
+func (c *Context) DivMod(x, y *Decimal, mode DivisionMode) (quo, rem *Decimal, err error) {
+	v1, w1 := x.ToUnscaledBigInt()
+	v2, w2 := y.ToUnscaledBigInt()
+	if v2.Sign() == 0 {
+		if v1.Sign() == 0 {
+			_, err = c.result(nil, false, ErrBadNumber)
+		} else {
+			_, err = c.result(nil, false, ErrDivByZero)
+		}
+		return nil, nil, err
+	}
+	a1, a2, w := alignUnboundedDecimals(NewUnboundedDecFromDecimal(x), NewUnboundedDecFromDecimal(y))
+	q, r := new(big.Int).QuoRem(a1, a2, new(big.Int))
+	// adjust the truncated quotient by one if the remainder has the wrong sign.
+	switch {
+	case r.Sign() == 0 || mode == DivisionTruncated:
+	case mode == DivisionEuclidean && r.Sign() < 0 && a2.Sign() < 0:
+		q.Add(q, bigOne)
+		r.Sub(r, a2)
+	case mode == DivisionEuclidean && r.Sign() < 0, mode == DivisionFloored && r.Sign() != a2.Sign():
+		q.Sub(q, bigOne)
+		r.Add(r, a2)
+	}
+	scale := myMax(int(w1-w2), 0)
+	quo, err = c.roundExact(q.Mul(q, bigPow10(scale)), scale)
+	rem, remErr := c.roundExact(r, w)
+	return quo, rem, firstErr(err, remErr)
+}
+
+// SetScale returns x with scale digits after the point like Decimal.SetScale, x is rounded with RoundMode,
+// and then to the context.
+func (c *Context) SetScale(x *Decimal, scale int) (*Decimal, error) {
+	u := NewUnboundedDecFromDecimal(x)
+	var rounded UnboundedDecimal
+	if err := u.Round(&rounded, scale, c.RoundMode); err != nil {
+		return c.result(nil, false, err)
+	}
+	to, inexact, err := roundFixed(&rounded.value, rounded.digitsFrac, 0, c.round)
+	return c.result(to, inexact || rounded.Compare(u) != 0, err)
+}
+
+// MovePointLeft returns x * 10^-n with the scale max(scale + n, 0) like Decimal.MovePointLeft.
+func (c *Context) MovePointLeft(x *Decimal, n int) (*Decimal, error) {
+	if n < -maxPowExponent || n > maxPowExponent {
+		return c.result(nil, false, ErrBadNumber)
+	}
+	return c.movePoint(x, -n)
+}
+
+// MovePointRight returns x * 10^n with the scale max(scale - n, 0) like Decimal.MovePointRight.
+func (c *Context) MovePointRight(x *Decimal, n int) (*Decimal, error) {
+	if n < -maxPowExponent || n > maxPowExponent {
+		return c.result(nil, false, ErrBadNumber)
+	}
+	return c.movePoint(x, n)
+}
+
+// movePoint returns x * 10^n, the size of the result is checked before the power of 10 is computed.
+func (c *Context) movePoint(x *Decimal, n int) (*Decimal, error) {
+	v, w := x.ToUnscaledBigInt()
+	maxDigits := wordBufLen * digitsPerWord
+	scale := int(w) - n
+	if v.Sign() == 0 {
+		return c.roundExact(v, myMin(myMax(scale, 0), maxDigits))
+	}
+	digits := len(new(big.Int).Abs(v).Text(10))
+	switch {
+	case digits-scale > maxDigits+1:
+		return c.roundExact(signedPow10(maxDigits+1, v.Sign() < 0), 0)
+	case scale-digits > maxDigits+2:
+		return c.result(roundFixed(new(big.Int), maxDigits+1, v.Sign(), c.round))
+	case scale < 0:
+		return c.roundExact(v.Mul(v, bigPow10(-scale)), 0)
+	}
+	return c.roundExact(v, scale)
+}
+
+// Sum returns the sum of decs, it is 0 if decs is empty.
+func (c *Context) Sum(decs []*Decimal) (*Decimal, error) {
+	sum, w := c.sum(decs)
+	return c.roundExact(sum, w)
+}
+
+// Avg returns the average of decs, or nil if decs is empty.
+func (c *Context) Avg(decs []*Decimal) (*Decimal, error) {
+	if len(decs) == 0 {
+		return nil, nil
+	}
+	sum, w := c.sum(decs)
+	return c.quo(sum, w, big.NewInt(int64(len(decs))), 0)
+}
+
+// sum returns the exact sum of decs as a fixed-point value with w digits.
+func (c *Context) sum(decs []*Decimal) (v *big.Int, w int) {
+	var sum UnboundedDecimal
+	for _, d := range decs {
+		v1, v2, frac := alignUnboundedDecimals(&sum, NewUnboundedDecFromDecimal(d))
+		sum.value.Add(v1, v2)
+		sum.digitsFrac = frac
+	}
+	return &sum.value, sum.digitsFrac
+}
+
+// Sqrt returns the square root of x.
+func (c *Context) Sqrt(x *Decimal) (*Decimal, error) {
+	return c.result(decimalSqrt(x, c.maxScale(), c.round))
+}
+
+// Exp returns e^x.
+func (c *Context) Exp(x *Decimal) (*Decimal, error) {
+	return c.result(decimalExp(x, c.maxScale(), c.round))
+}
+
+// Ln returns the natural logarithm of x.
+func (c *Context) Ln(x *Decimal) (*Decimal, error) {
+	return c.result(decimalLn(x, c.maxScale(), c.round))
+}
+
+// Log10 returns the common logarithm of x.
+func (c *Context) Log10(x *Decimal) (*Decimal, error) {
+	return c.result(decimalLog10(x, c.maxScale(), c.round))
+}
+
+// setResult sets to the result of a Context operation, to is untouched if there is no result.
+func setResult(to, result *Decimal, err error) error {
+	if result != nil {
+		*to = *result
+	}
+	return err
+}
+
+// DecimalAddWithContext sets to = from1 + from2 rounded to ctx, it is DecimalAdd if ctx is nil.
+func DecimalAddWithContext(from1, from2, to *Decimal, ctx *Context) error {
+	if ctx == nil {
+		return DecimalAdd(from1, from2, to)
+	}
+	result, err := ctx.Add(from1, from2)
+	return setResult(to, result, err)
+}
+
+// DecimalSubWithContext sets to = from1 - from2 rounded to ctx, it is DecimalSub if ctx is nil.
+func DecimalSubWithContext(from1, from2, to *Decimal, ctx *Context) error {
+	if ctx == nil {
+		return DecimalSub(from1, from2, to)
+	}
+	result, err := ctx.Sub(from1, from2)
+	return setResult(to, result, err)
+}
+
+// DecimalMulWithContext sets to = from1 * from2 rounded to ctx, it is DecimalMul if ctx is nil.
+func DecimalMulWithContext(from1, from2, to *Decimal, ctx *Context) error {
+	if ctx == nil {
+		return DecimalMul(from1, from2, to)
+	}
+	result, err := ctx.Mul(from1, from2)
+	return setResult(to, result, err)
+}
+
+// DecimalDivWithContext sets to = from1 / from2 rounded to ctx, it is DecimalDiv with DivFracIncr if ctx is nil.
+func DecimalDivWithContext(from1, from2, to *Decimal, ctx *Context) error {
+	if ctx == nil {
+		return DecimalDiv(from1, from2, to, DivFracIncr)
+	}
+	result, err := ctx.Quo(from1, from2)
+	return setResult(to, result, err)
+}
+
+// DecimalModWithContext sets to the remainder of from1 / from2 rounded to ctx, it is DecimalMod if ctx is nil.
+func DecimalModWithContext(from1, from2, to *Decimal, ctx *Context) error {
+	if ctx == nil {
+		return DecimalMod(from1, from2, to)
+	}
+	result, err := ctx.Rem(from1, from2)
+	return setResult(to, result, err)
+}
+
+// DecimalDivModWithContext sets quo and rem to the integral quotient and the remainder of from1 / from2
+// rounded to ctx, it is DecimalDivModWithMode if ctx is nil. quo and rem are untouched if there is no result.
+// Note: DO NOT use `from1` or `from2` as `quo` or `rem`.
+func DecimalDivModWithContext(from1, from2, quo, rem *Decimal, mode DivisionMode, ctx *Context) error {
+	if ctx == nil {
+		return DecimalDivModWithMode(from1, from2, quo, rem, mode)
+	}
+	q, r, err := ctx.DivMod(from1, from2, mode)
+	_ = setResult(quo, q, nil)
+	return setResult(rem, r, err)
+}
+
+// ShiftWithContext multiplies d by 10^shift rounded to ctx, it is Shift if ctx is nil.
+// The result has the scale max(scale - shift, 0) like MovePointRight.
+func (d *Decimal) ShiftWithContext(shift int, ctx *Context) error {
+	if ctx == nil {
+		return d.Shift(shift)
+	}
+	result, err := ctx.movePoint(d, myMin(myMax(shift, -maxPowExponent), maxPowExponent))
+	return setResult(d, result, err)
+}
+
+// RoundWithContext rounds d to ctx and sets the result to to, d == to is allowed.
+// It copies d to to if ctx is nil.
+func (d *Decimal) RoundWithContext(to *Decimal, ctx *Context) error {
+	if ctx == nil {
+		*to = *d
+		return nil
+	}
+	result, err := ctx.Round(d)
+	return setResult(to, result, err)
+}
@@ -0,0 +1,293 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxbig
+
+import (
+	"strings"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContextArithmetic(t *testing.T) {
+	ctx := NewContext(5, 10, ModeHalfToEven)
+	tests := []struct {
+		op     func(x, y *Decimal) (*Decimal, error)
+		x, y   string
+		output string
+		flags  Condition
+	}{
+		{ctx.Add, "1.25", "2.5", "3.75", 0},
+		{ctx.Add, "99999", "0.5", "100000", ConditionInexact},
+		{ctx.Add, "12345.5", "0", "12346", ConditionInexact},
+		{ctx.Sub, "1", "0.000000000001", "1.0000", ConditionInexact},
+		{ctx.Mul, "1.5", "1.5", "2.25", 0},
+		{ctx.Mul, "123.45", "67.89", "8381.0", ConditionInexact},
+		{ctx.Quo, "1", "3", "0.33333", ConditionInexact},
+		{ctx.Quo, "-2", "3", "-0.66667", ConditionInexact},
+		{ctx.Quo, "1", "8", "0.125", 0},
+		{ctx.Quo, "1", "30000000000", "0.0000000000", ConditionInexact},
+		{ctx.Quo, "1234567", "1", "1234600", ConditionInexact},
+		{ctx.Rem, "10", "3", "1", 0},
+		{ctx.Rem, "-7.5", "2", "-1.5", 0},
+	}
+	for _, ca := range tests {
+		ctx.Flags = 0
+		result, err := ca.op(NewDecFromStringForTest(ca.x), NewDecFromStringForTest(ca.y))
+		assert.Nil(t, err, ca.x+" "+ca.y)
+		assert.Equal(t, ca.output, string(result.ToBytes()), ca.x+" "+ca.y)
+		assert.Equal(t, ca.flags, ctx.Flags, ca.x+" "+ca.y)
+	}
+}
+
+func TestContextOutParameters(t *testing.T) {
+	tests := []struct {
+		op        func(from1, from2, to *Decimal, ctx *Context) error
+		x, y      string
+		output    string
+		outputNil string
+	}{
+		{DecimalAddWithContext, "1.25", "2.5", "3.8", "3.75"},
+		{DecimalSubWithContext, "1.25", "2.5", "-1.3", "-1.25"},
+		{DecimalMulWithContext, "1.25", "2.5", "3.1", "3.125"},
+		{DecimalDivWithContext, "1", "3", "0.3", "0.3333"},
+		{DecimalModWithContext, "10.25", "3", "1.3", "1.25"},
+	}
+	ctx := NewContext(0, 1, ModeHalfUp)
+	for _, ca := range tests {
+		x, y := NewDecFromStringForTest(ca.x), NewDecFromStringForTest(ca.y)
+		var to Decimal
+		assert.Nil(t, ca.op(x, y, &to, ctx), ca.x+" "+ca.y)
+		assert.Equal(t, ca.output, to.String(), ca.x+" "+ca.y)
+		assert.Nil(t, ca.op(x, y, &to, nil), ca.x+" "+ca.y)
+		assert.Equal(t, ca.outputNil, to.String(), ca.x+" "+ca.y)
+	}
+
+	// to is untouched if there is no result.
+	to := NewDecFromInt(7)
+	assert.Equal(t, ErrDivByZero, DecimalDivWithContext(NewDecFromInt(1), NewDecFromInt(0), to, ctx))
+	assert.Equal(t, "7", to.String())
+
+	d := NewDecFromStringForTest("2.675")
+	assert.Nil(t, d.RoundWithContext(to, nil))
+	assert.Equal(t, "2.675", to.String())
+	assert.Nil(t, d.RoundWithContext(d, NewContext(3, 30, ModeHalfToEven)))
+	assert.Equal(t, "2.68", d.String())
+}
+
+func TestContextConditions(t *testing.T) {
+	ctx := NewContext(0, 2, ModeHalfUp)
+	one, zero := NewDecFromInt(1), NewDecFromInt(0)
+
+	_, err := ctx.Quo(one, zero)
+	assert.Equal(t, ErrDivByZero, err)
+	_, err = ctx.Quo(zero, zero)
+	assert.Equal(t, ErrBadNumber, err)
+	_, err = ctx.Rem(one, zero)
+	assert.Equal(t, ErrBadNumber, err)
+	_, err = ctx.Sqrt(NewDecFromInt(-1))
+	assert.Equal(t, ErrBadNumber, err)
+	max := NewDecFromStringForTest(strings.Repeat("9", 81))
+	result, err := ctx.Add(max, one)
+	assert.Equal(t, ErrOverflow, err)
+	assert.Equal(t, strings.Repeat("9", 81), string(result.ToBytes()))
+	assert.Equal(t, DefaultTraps, ctx.Flags)
+	assert.Equal(t, "overflow|division by zero|invalid operation", ctx.Flags.String())
+
+	// the untrapped conditions are only flagged.
+	ctx.Traps, ctx.Flags = 0, 0
+	result, err = ctx.Quo(one, zero)
+	assert.Nil(t, err)
+	assert.Nil(t, result)
+	assert.Equal(t, ConditionDivisionByZero, ctx.Flags)
+
+	ctx.Traps, ctx.Flags = ConditionInexact, 0
+	result, err = ctx.Quo(one, NewDecFromInt(3))
+	assert.Equal(t, ErrTruncated, err)
+	assert.Equal(t, "0.33", string(result.ToBytes()))
+	assert.Equal(t, ConditionInexact, ctx.Flags)
+
+	ctx.RoundMode = ModeUnnecessary
+	_, err = ctx.Quo(one, NewDecFromInt(3))
+	assert.Equal(t, ErrRoundingNecessary, err)
+	result, err = ctx.Quo(one, NewDecFromInt(4))
+	assert.Nil(t, err)
+	assert.Equal(t, "0.25", string(result.ToBytes()))
+}
+
+func TestContextFunctions(t *testing.T) {
+	ctx := NewMySQLContext()
+	tests := []struct {
+		op     func(x *Decimal) (*Decimal, error)
+		x      string
+		output string
+	}{
+		{ctx.Round, "1.0000000000000000000000000000005", "1.000000000000000000000000000001"},
+		{ctx.Neg, "1.5", "-1.5"},
+		{ctx.Abs, "-1.5", "1.5"},
+		{ctx.Sqrt, "2", "1.414213562373095048801688724210"},
+		{ctx.Sqrt, "0.25", "0.5"},
+		{ctx.Exp, "1", "2.718281828459045235360287471353"},
+		{ctx.Ln, "10", "2.302585092994045684017991454684"},
+		{ctx.Log10, "1000", "3"},
+		{NewContext(4, 30, ModeHalfUp).Exp, "10", "22030"},
+		{NewContext(4, 30, ModeHalfUp).Ln, "0.5", "-0.6931"},
+	}
+	for _, ca := range tests {
+		result, err := ca.op(NewDecFromStringForTest(ca.x))
+		assert.Nil(t, err, ca.x)
+		assert.Equal(t, ca.output, string(result.ToBytes()), ca.x)
+	}
+
+	result, err := ctx.Pow(NewDecFromStringForTest("1.1"), 2)
+	assert.Nil(t, err)
+	assert.Equal(t, "1.21", string(result.ToBytes()))
+	result, err = ctx.Pow(NewDecFromStringForTest("2"), -2)
+	assert.Nil(t, err)
+	assert.Equal(t, "0.25", string(result.ToBytes()))
+	result, err = NewContext(3, 30, ModeHalfUp).Pow(NewDecFromStringForTest("3"), -1)
+	assert.Nil(t, err)
+	assert.Equal(t, "0.333", string(result.ToBytes()))
+	_, err = ctx.Pow(NewDecFromInt(0), -1)
+	assert.Equal(t, ErrDivByZero, err)
+
+	// the size of the huge powers is bounded before computing them.
+	ctx = NewContext(10, 30, ModeHalfUp)
+	_, err = ctx.Pow(NewDecFromInt(2), 999999999)
+	assert.Equal(t, ErrOverflow, err)
+	ctx.Traps, ctx.Flags = 0, 0
+	result, err = ctx.Pow(NewDecFromInt(2), -999999999)
+	assert.Nil(t, err)
+	assert.Equal(t, "0.000000000000000000000000000000", string(result.ToBytes()))
+	assert.Equal(t, ConditionInexact, ctx.Flags)
+	result, _ = NewContext(10, 30, ModeUp).Pow(NewDecFromStringForTest("-0.5"), 999999999)
+	assert.Equal(t, "-0.000000000000000000000000000001", string(result.ToBytes()))
+	result, err = ctx.Pow(NewDecFromStringForTest("1.00000001"), 999999999)
+	assert.Nil(t, err)
+	assert.Equal(t, "22026.46447", string(result.ToBytes()))
+	result, err = ctx.Pow(NewDecFromStringForTest("1.00000001"), -999999999)
+	assert.Nil(t, err)
+	assert.Equal(t, "0.00004539993249", string(result.ToBytes()))
+	result, err = ctx.Pow(NewDecFromStringForTest("-1.00"), -999999999)
+	assert.Nil(t, err)
+	assert.Equal(t, "-1.000000000", string(result.ToBytes()))
+}
+
+func TestContextScaleAndDivMod(t *testing.T) {
+	ctx := NewContext(5, 10, ModeHalfUp)
+	tests := []struct {
+		op     func(x *Decimal) (*Decimal, error)
+		x      string
+		output string
+		flags  Condition
+	}{
+		{func(x *Decimal) (*Decimal, error) { return ctx.SetScale(x, 3) }, "1.5", "1.500", 0},
+		{func(x *Decimal) (*Decimal, error) { return ctx.SetScale(x, 1) }, "1.25", "1.3", ConditionInexact},
+		{func(x *Decimal) (*Decimal, error) { return ctx.SetScale(x, -2) }, "1250", "1300", ConditionInexact},
+		{func(x *Decimal) (*Decimal, error) { return ctx.SetScale(x, 3) }, "123.4", "123.40", 0},
+		{func(x *Decimal) (*Decimal, error) { return ctx.MovePointLeft(x, 2) }, "123.4", "1.234", 0},
+		{func(x *Decimal) (*Decimal, error) { return ctx.MovePointLeft(x, 3) }, "123.45", "0.12345", 0},
+		{func(x *Decimal) (*Decimal, error) { return ctx.MovePointLeft(x, 4) }, "123.45", "0.012345", 0},
+		{func(x *Decimal) (*Decimal, error) { return ctx.MovePointLeft(x, 10) }, "12345", "0.0000012345", 0},
+		{func(x *Decimal) (*Decimal, error) { return ctx.MovePointLeft(x, 12) }, "12345", "0.0000000123", ConditionInexact},
+		{func(x *Decimal) (*Decimal, error) { return ctx.MovePointRight(x, 3) }, "1.5", "1500", 0},
+		{func(x *Decimal) (*Decimal, error) { return ctx.MovePointRight(x, 3) }, "1.23456", "1234.6", ConditionInexact},
+		{func(x *Decimal) (*Decimal, error) { return ctx.MovePointRight(x, -1) }, "-1.5", "-0.15", 0},
+		{func(x *Decimal) (*Decimal, error) { return ctx.MovePointRight(x, 999999999) }, "0", "0", 0},
+		{func(x *Decimal) (*Decimal, error) { return ctx.MovePointLeft(x, 999999999) }, "5", "0.0000000000", ConditionInexact},
+	}
+	for _, ca := range tests {
+		ctx.Flags = 0
+		result, err := ca.op(NewDecFromStringForTest(ca.x))
+		assert.Nil(t, err, ca.x+" "+ca.output)
+		assert.Equal(t, ca.output, string(result.ToBytes()), ca.x+" "+ca.output)
+		assert.Equal(t, ca.flags, ctx.Flags, ca.x+" "+ca.output)
+	}
+
+	_, err := ctx.MovePointRight(NewDecFromInt(1), 999999999)
+	assert.Equal(t, ErrOverflow, err)
+	_, err = ctx.MovePointLeft(NewDecFromInt(1), 1000000000)
+	assert.Equal(t, ErrBadNumber, err)
+	_, err = NewContext(0, 2, ModeUnnecessary).SetScale(NewDecFromStringForTest("1.25"), 1)
+	assert.Equal(t, ErrRoundingNecessary, err)
+
+	d := NewDecFromStringForTest("1.23456")
+	assert.Nil(t, d.ShiftWithContext(2, ctx))
+	assert.Equal(t, "123.46", string(d.ToBytes()))
+	assert.Equal(t, "123.46", d.JavaValue)
+	assert.Nil(t, d.ShiftWithContext(1, nil))
+	assert.Equal(t, "1234.6", string(d.ToBytes()))
+
+	divMods := []struct {
+		x, y     string
+		mode     DivisionMode
+		quo, rem string
+	}{
+		{"7.5", "2", DivisionTruncated, "3.0", "1.5"},
+		{"-7.5", "2", DivisionTruncated, "-3.0", "-1.5"},
+		{"-7.5", "2", DivisionFloored, "-4.0", "0.5"},
+		{"7.5", "-2", DivisionFloored, "-4.0", "-0.5"},
+		{"-7.5", "-2", DivisionEuclidean, "4.0", "0.5"},
+		{"-7.5", "2", DivisionEuclidean, "-4.0", "0.5"},
+		{"1.25", "0.5", DivisionTruncated, "2.0", "0.25"},
+		{"123456789", "1", DivisionTruncated, "123460000", "0"},
+	}
+	for _, ca := range divMods {
+		x, y := NewDecFromStringForTest(ca.x), NewDecFromStringForTest(ca.y)
+		var quo, rem Decimal
+		assert.Nil(t, DecimalDivModWithContext(x, y, &quo, &rem, ca.mode, NewContext(5, 10, ModeHalfUp)), ca.x+" "+ca.y)
+		assert.Equal(t, ca.quo, string(quo.ToBytes()), ca.x+" "+ca.y)
+		assert.Equal(t, ca.rem, string(rem.ToBytes()), ca.x+" "+ca.y)
+	}
+	var quo, rem Decimal
+	assert.Nil(t, DecimalDivModWithContext(NewDecFromStringForTest("-7.5"), NewDecFromInt(2), &quo, &rem, DivisionFloored, nil))
+	assert.Equal(t, "-4.0", quo.String())
+	assert.Equal(t, "0.5", rem.String())
+	assert.Equal(t, ErrDivByZero, DecimalDivModWithContext(NewDecFromInt(1), NewDecFromInt(0), &quo, &rem, DivisionTruncated, ctx))
+	_, _, err = ctx.DivMod(NewDecFromInt(0), NewDecFromInt(0), DivisionTruncated)
+	assert.Equal(t, ErrBadNumber, err)
+}
+
+func TestContextAggregate(t *testing.T) {
+	ctx := NewContext(5, 4, ModeHalfUp)
+	decs := newDecsForTest("1.5", "-2.25", "10", "3.125")
+	sum, err := ctx.Sum(decs)
+	assert.Nil(t, err)
+	assert.Equal(t, "12.375", string(sum.ToBytes()))
+	avg, err := ctx.Avg(decs)
+	assert.Nil(t, err)
+	assert.Equal(t, "3.0938", string(avg.ToBytes()))
+	assert.Equal(t, ConditionInexact, ctx.Flags)
+
+	sum, err = ctx.Sum(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "0", string(sum.ToBytes()))
+	avg, err = ctx.Avg(nil)
+	assert.Nil(t, err)
+	assert.Nil(t, avg)
+
+	// the exact sum doesn't overflow in the middle.
+	max := strings.Repeat("9", 81)
+	sum, err = NewContext(0, 0, ModeHalfUp).Sum(newDecsForTest(max, "1", "-2"))
+	assert.Nil(t, err)
+	assert.Equal(t, strings.Repeat("9", 80)+"8", string(sum.ToBytes()))
+	_, err = ctx.Sum(newDecsForTest(max, "1"))
+	assert.Equal(t, ErrOverflow, err)
+}
@@ -0,0 +1,171 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxbig
+
+import (
+	"bytes"
+	"database/sql/driver"
+)
+
+import (
+	"github.com/pkg/errors"
+)
+
+var nullJSON = []byte("null")
+
+// MarshalJSON implements json.Marshaler, the decimal is a string like "1.23" as most JSON
+// decoders parse numbers into float64 and lose precision. Use DecimalNumber for a bare number.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	str := d.ToBytes()
+	buf := make([]byte, 0, len(str)+2)
+	buf = append(buf, '"')
+	buf = append(buf, str...)
+	buf = append(buf, '"')
+	return buf, nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, it accepts both a string and a bare number.
+// As other types, null leaves the decimal unchanged.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, nullJSON) {
+		return nil
+	}
+	if len(data) >= 2 && data[0] == '"' && data[len(data)-1] == '"' {
+		data = data[1 : len(data)-1]
+	}
+	return d.UnmarshalText(data)
+}
+
+// DecimalNumber is a Decimal that marshals JSON as a bare number like 1.23 instead of a string,
+// e.g. a struct field of type DecimalNumber or DecimalNumber(d) passed to json.Marshal.
+type DecimalNumber Decimal
+
+// MarshalJSON implements json.Marshaler.
+func (n DecimalNumber) MarshalJSON() ([]byte, error) {
+	d := Decimal(n)
+	return d.ToBytes(), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, it accepts both a string and a bare number as Decimal.
+func (n *DecimalNumber) UnmarshalJSON(data []byte) error {
+	return (*Decimal)(n).UnmarshalJSON(data)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Decimal) MarshalText() ([]byte, error) {
+	return d.ToBytes(), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Decimal) UnmarshalText(text []byte) error {
+	*d = zeroBigDecimal
+	if err := d.FromBytes(text); err != nil {
+		return errors.Wrapf(err, "can't unmarshal %q into Decimal", text)
+	}
+	return nil
+}
+
+// Scan implements sql.Scanner. MySQL returns DECIMAL columns as text in both the text and
+// the binary protocol, numbers returned by other drivers are accepted as well.
+// Use BinDecimal to scan the binary layout of ToBin, and NullDecimal for a nullable column.
+func (d *Decimal) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case []byte:
+		return d.UnmarshalText(v)
+	case string:
+		return d.UnmarshalText([]byte(v))
+	case int64:
+		*d = zeroBigDecimal
+		d.FromInt(v)
+		return nil
+	case float64:
+		*d = zeroBigDecimal
+		return d.FromFloat64(v)
+	case nil:
+		return errors.New("can't scan NULL into Decimal")
+	default:
+		return errors.Errorf("can't scan %T into Decimal", value)
+	}
+}
+
+// Value implements driver.Valuer, the decimal is sent to the database as a string like NullDecimal.
+func (d Decimal) Value() (driver.Value, error) {
+	return string(d.ToBytes()), nil
+}
+
+// NullDecimal represents a Decimal that may be null like sql.NullString,
+// it implements sql.Scanner and driver.Valuer.
+type NullDecimal struct {
+	Decimal Decimal
+	Valid   bool // Valid is true if Decimal is not NULL
+}
+
+// Scan implements sql.Scanner.
+func (n *NullDecimal) Scan(value interface{}) error {
+	if value == nil {
+		n.Decimal, n.Valid = zeroBigDecimal, false
+		return nil
+	}
+	n.Valid = true
+	return n.Decimal.Scan(value)
+}
+
+// Value implements driver.Valuer, the decimal is sent to the database as a string.
+func (n NullDecimal) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return string(n.Decimal.ToBytes()), nil
+}
+
+// BinDecimal scans and values a Decimal in the binary layout of ToBin/FromBin,
+// e.g. a DECIMAL(Precision, Frac) column stored by MySQL.
+type BinDecimal struct {
+	Dec       *Decimal
+	Precision int
+	Frac      int
+}
+
+// Scan implements sql.Scanner.
+func (b BinDecimal) Scan(value interface{}) error {
+	bin, ok := value.([]byte)
+	if !ok {
+		return errors.Errorf("can't scan %T into BinDecimal", value)
+	}
+	if b.Dec == nil {
+		return errors.New("can't scan into BinDecimal with a nil Dec")
+	}
+	*b.Dec = zeroBigDecimal
+	binSize, err := b.Dec.FromBin(bin, b.Precision, b.Frac)
+	if err != nil {
+		return err
+	}
+	if binSize != len(bin) {
+		return errors.Errorf("BinDecimal of precision %d and frac %d needs %d bytes, got %d",
+			b.Precision, b.Frac, binSize, len(bin))
+	}
+	return nil
+}
+
+// Value implements driver.Valuer.
+func (b BinDecimal) Value() (driver.Value, error) {
+	if b.Dec == nil {
+		return nil, errors.New("can't value BinDecimal with a nil Dec")
+	}
+	return b.Dec.ToBin(b.Precision, b.Frac)
+}
@@ -0,0 +1,170 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxbig
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"encoding/json"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	_ json.Marshaler           = Decimal{}
+	_ json.Unmarshaler         = &Decimal{}
+	_ json.Marshaler           = DecimalNumber{}
+	_ json.Unmarshaler         = &DecimalNumber{}
+	_ encoding.TextMarshaler   = Decimal{}
+	_ encoding.TextUnmarshaler = &Decimal{}
+	_ sql.Scanner              = &Decimal{}
+	_ driver.Valuer            = Decimal{}
+	_ driver.Valuer            = &Decimal{}
+	_ sql.Scanner              = &NullDecimal{}
+	_ driver.Valuer            = NullDecimal{}
+	_ sql.Scanner              = BinDecimal{}
+	_ driver.Valuer            = BinDecimal{}
+)
+
+type price struct {
+	Amount   Decimal  `json:"amount"`
+	Discount *Decimal `json:"discount,omitempty"`
+}
+
+type numberPrice struct {
+	Amount   DecimalNumber  `json:"amount"`
+	Discount *DecimalNumber `json:"discount,omitempty"`
+}
+
+func TestDecimalJSON(t *testing.T) {
+	p := price{Amount: *NewDecFromStringForTest("-123.4500"), Discount: NewDecFromInt(3)}
+	data, err := json.Marshal(p)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"amount":"-123.4500","discount":"3"}`, string(data))
+
+	n := numberPrice{Amount: DecimalNumber(p.Amount), Discount: (*DecimalNumber)(p.Discount)}
+	data, err = json.Marshal(n)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"amount":-123.4500,"discount":3}`, string(data))
+	var back numberPrice
+	assert.Nil(t, json.Unmarshal(data, &back))
+	assert.Equal(t, "-123.4500", string((*Decimal)(&back.Amount).ToBytes()))
+	assert.Equal(t, "3", string((*Decimal)(back.Discount).ToBytes()))
+
+	// the string of Decimal is accepted as well.
+	assert.Nil(t, json.Unmarshal([]byte(`{"amount":"0.5"}`), &back))
+	assert.Equal(t, "0.5", string((*Decimal)(&back.Amount).ToBytes()))
+
+	tests := []struct {
+		input  string
+		output string
+	}{
+		{`{"amount":"-123.4500"}`, "-123.4500"},
+		{`{"amount":-123.4500}`, "-123.4500"},
+		{`{"amount":1.23E+5}`, "123000"},
+		{`{"amount":"123456789012345678901234567890.123456789"}`, "123456789012345678901234567890.123456789"},
+	}
+	for _, ca := range tests {
+		var result price
+		assert.Nil(t, json.Unmarshal([]byte(ca.input), &result), ca.input)
+		assert.Equal(t, ca.output, string(result.Amount.ToBytes()), ca.input)
+		assert.Equal(t, ca.output, result.Amount.JavaValue, ca.input)
+	}
+
+	// null leaves the decimal unchanged.
+	result := price{Amount: *NewDecFromInt(1)}
+	assert.Nil(t, json.Unmarshal([]byte(`{"amount":null}`), &result))
+	assert.Equal(t, "1", string(result.Amount.ToBytes()))
+
+	assert.NotNil(t, json.Unmarshal([]byte(`{"amount":"abc"}`), &result))
+	assert.NotNil(t, json.Unmarshal([]byte(`{"amount":""}`), &result))
+}
+
+func TestDecimalText(t *testing.T) {
+	var dec Decimal
+	assert.Nil(t, dec.UnmarshalText([]byte("-0.001")))
+	text, err := dec.MarshalText()
+	assert.Nil(t, err)
+	assert.Equal(t, "-0.001", string(text))
+	assert.Nil(t, dec.UnmarshalText([]byte("5")))
+	assert.False(t, dec.IsNegative())
+	assert.NotNil(t, dec.UnmarshalText(nil))
+}
+
+func TestDecimalScan(t *testing.T) {
+	tests := []struct {
+		input  interface{}
+		output string
+	}{
+		{[]byte("123.45"), "123.45"},
+		{"-0.00100", "-0.00100"},
+		{int64(-42), "-42"},
+		{float64(1.5), "1.5"},
+	}
+	for _, ca := range tests {
+		var dec Decimal
+		assert.Nil(t, dec.Scan(ca.input))
+		assert.Equal(t, ca.output, string(dec.ToBytes()))
+	}
+
+	var dec Decimal
+	assert.NotNil(t, dec.Scan(nil))
+	assert.NotNil(t, dec.Scan(true))
+
+	value, err := NewDecFromStringForTest("-0.00100").Value()
+	assert.Nil(t, err)
+	assert.Equal(t, "-0.00100", value)
+	value, err = Decimal{}.Value()
+	assert.Nil(t, err)
+	assert.Equal(t, "0", value)
+
+	var null NullDecimal
+	assert.Nil(t, null.Scan("1.10"))
+	assert.True(t, null.Valid)
+	value, err = null.Value()
+	assert.Nil(t, err)
+	assert.Equal(t, "1.10", value)
+	assert.Nil(t, null.Scan(nil))
+	assert.False(t, null.Valid)
+	value, err = null.Value()
+	assert.Nil(t, err)
+	assert.Nil(t, value)
+}
+
+func TestBinDecimal(t *testing.T) {
+	from := BinDecimal{Dec: NewDecFromStringForTest("-1234567890.1234"), Precision: 14, Frac: 4}
+	value, err := from.Value()
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0x7E, 0xF2, 0x04, 0xC7, 0x2D, 0xFB, 0x2D}, value)
+
+	to := BinDecimal{Dec: new(Decimal), Precision: 14, Frac: 4}
+	assert.Nil(t, to.Scan(value))
+	assert.Equal(t, "-1234567890.1234", string(to.Dec.ToBytes()))
+
+	assert.NotNil(t, to.Scan("-1234567890.1234"))
+
+	// a nil Dec is an error instead of a panic.
+	assert.NotNil(t, BinDecimal{Precision: 14, Frac: 4}.Scan(value))
+	_, err = BinDecimal{Precision: 14, Frac: 4}.Value()
+	assert.NotNil(t, err)
+	assert.NotNil(t, to.Scan([]byte{0x7E, 0xF2, 0x04, 0xC7, 0x2D, 0xFB, 0x2D, 0x00}))
+}
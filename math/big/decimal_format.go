@@ -0,0 +1,598 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxbig
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+import (
+	"github.com/pkg/errors"
+)
+
+// DecimalFormatSymbols are the localized symbols used by DecimalFormat, like java.text.DecimalFormatSymbols.
+type DecimalFormatSymbols struct {
+	DecimalSeparator  rune
+	GroupingSeparator rune
+	MinusSign         rune
+	Percent           rune
+	PerMill           rune
+	ExponentSeparator string
+}
+
+// DefaultDecimalFormatSymbols are the symbols of the root locale.
+var DefaultDecimalFormatSymbols = DecimalFormatSymbols{
+	DecimalSeparator:  '.',
+	GroupingSeparator: ',',
+	MinusSign:         '-',
+	Percent:           '%',
+	PerMill:           '‰',
+	ExponentSeparator: "E",
+}
+
+const (
+	patternDigit            = '#'
+	patternZeroDigit        = '0'
+	patternGroupingSep      = ','
+	patternDecimalSep       = '.'
+	patternExponent         = 'E'
+	patternSeparator        = ';'
+	patternPercent          = '%'
+	patternPerMill          = '‰'
+	patternMinus            = '-'
+	patternQuote            = '\''
+	patternMaxIntegerDigits = math.MaxInt32
+)
+
+// affixPart is a literal text, or a symbol replaced by DecimalFormatSymbols.
+type affixPart struct {
+	text   string
+	symbol rune
+}
+
+type affix []affixPart
+
+func (a affix) expand(symbols *DecimalFormatSymbols) string {
+	var buf strings.Builder
+	for _, part := range a {
+		switch part.symbol {
+		case patternPercent:
+			buf.WriteRune(symbols.Percent)
+		case patternPerMill:
+			buf.WriteRune(symbols.PerMill)
+		case patternMinus:
+			buf.WriteRune(symbols.MinusSign)
+		default:
+			buf.WriteString(part.text)
+		}
+	}
+	return buf.String()
+}
+
+// DecimalFormat formats and parses decimals with the patterns of java.text.DecimalFormat, e.g. "#,##0.00",
+// "0.###E0", "#0.#%" and "#,##0.00;(#,##0.00)". The digits are taken from the words of Decimal
+// and never go through float64.
+//
+// Like Java, the rounding defaults to ModeHalfToEven. Unlike Java, a negative value rounded to zero
+// is formatted without the minus sign, and the significant digit patterns with '@' and the currency
+// sign are not supported.
+type DecimalFormat struct {
+	positivePrefix, positiveSuffix affix
+	negativePrefix, negativeSuffix affix
+	hasNegativePattern             bool
+
+	// the expanded affixes.
+	posPrefix, posSuffix string
+	negPrefix, negSuffix string
+
+	multiplier       int
+	groupingSize     int
+	minIntegerDigits int
+	maxIntegerDigits int
+	minFracDigits    int
+	maxFracDigits    int
+	decimalSepShown  bool
+	useExponent      bool
+	minExpDigits     int
+	roundMode        RoundMode
+	symbols          DecimalFormatSymbols
+}
+
+// NewDecimalFormat returns a DecimalFormat of pattern with DefaultDecimalFormatSymbols.
+func NewDecimalFormat(pattern string) (*DecimalFormat, error) {
+	f := &DecimalFormat{
+		multiplier: 1,
+		roundMode:  ModeHalfToEven,
+		symbols:    DefaultDecimalFormatSymbols,
+	}
+	if err := f.applyPattern(pattern); err != nil {
+		return nil, err
+	}
+	f.expandAffixes()
+	return f, nil
+}
+
+// SetSymbols sets the localized symbols, e.g. the decimal separator ',' and the grouping separator '.'.
+func (f *DecimalFormat) SetSymbols(symbols DecimalFormatSymbols) {
+	f.symbols = symbols
+	f.expandAffixes()
+}
+
+// SetRoundMode sets the round mode of Format.
+func (f *DecimalFormat) SetRoundMode(roundMode RoundMode) {
+	f.roundMode = roundMode
+}
+
+// SetGroupingSize sets the number of the integer digits between grouping separators, 0 disables grouping.
+func (f *DecimalFormat) SetGroupingSize(size int) {
+	f.groupingSize = size
+}
+
+func (f *DecimalFormat) expandAffixes() {
+	f.posPrefix = f.positivePrefix.expand(&f.symbols)
+	f.posSuffix = f.positiveSuffix.expand(&f.symbols)
+	if f.hasNegativePattern {
+		f.negPrefix = f.negativePrefix.expand(&f.symbols)
+		f.negSuffix = f.negativeSuffix.expand(&f.symbols)
+	} else {
+		f.negPrefix = string(f.symbols.MinusSign) + f.posPrefix
+		f.negSuffix = f.posSuffix
+	}
+}
+
+// applyPattern parses the pattern like java.text.DecimalFormat.applyPattern.
+func (f *DecimalFormat) applyPattern(pattern string) error {
+	subpatterns := splitPattern(pattern)
+	if len(subpatterns) > 2 {
+		return errors.Errorf("too many subpatterns in pattern %q", pattern)
+	}
+
+	prefix, number, suffix, err := f.parseSubpattern(subpatterns[0])
+	if err != nil {
+		return errors.Wrapf(err, "bad pattern %q", pattern)
+	}
+	if err = f.applyNumberPattern(number); err != nil {
+		return errors.Wrapf(err, "bad pattern %q", pattern)
+	}
+	f.positivePrefix, f.positiveSuffix = prefix, suffix
+	if len(subpatterns) == 2 {
+		// only the affixes of the negative subpattern are used.
+		if f.negativePrefix, _, f.negativeSuffix, err = f.parseSubpattern(subpatterns[1]); err != nil {
+			return errors.Wrapf(err, "bad pattern %q", pattern)
+		}
+		f.hasNegativePattern = true
+	}
+	return nil
+}
+
+// splitPattern splits the positive and the negative subpatterns by the unquoted ';'.
+func splitPattern(pattern string) []string {
+	var subpatterns []string
+	quoted, start := false, 0
+	for i, c := range pattern {
+		switch {
+		case c == patternQuote:
+			quoted = !quoted
+		case c == patternSeparator && !quoted:
+			subpatterns = append(subpatterns, pattern[start:i])
+			start = i + 1
+		}
+	}
+	return append(subpatterns, pattern[start:])
+}
+
+// parseSubpattern splits the subpattern into the prefix, the number pattern and the suffix.
+func (f *DecimalFormat) parseSubpattern(pattern string) (prefix affix, number string, suffix affix, err error) {
+	prefix, rest, err := f.parseAffix(pattern, true)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	end := numberPatternEnd(rest)
+	if end == 0 {
+		return nil, "", nil, errors.New("missing digits")
+	}
+	number = rest[:end]
+	suffix, rest, err = f.parseAffix(rest[end:], false)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	if rest != "" {
+		return nil, "", nil, errors.Errorf("unexpected %q after the suffix", rest)
+	}
+	return prefix, number, suffix, nil
+}
+
+// parseAffix parses the affix at the beginning of pattern, a prefix stops at the number pattern.
+func (f *DecimalFormat) parseAffix(pattern string, isPrefix bool) (a affix, rest string, err error) {
+	var literal strings.Builder
+	flush := func() {
+		if literal.Len() > 0 {
+			a = append(a, affixPart{text: literal.String()})
+			literal.Reset()
+		}
+	}
+	quoted := false
+	for i := 0; i < len(pattern); {
+		c, size := utf8.DecodeRuneInString(pattern[i:])
+		switch {
+		case c == patternQuote:
+			// '' is a quote both in and out of quotes, and the other text between quotes is literal.
+			if i+1 < len(pattern) && pattern[i+1] == patternQuote {
+				literal.WriteRune(patternQuote)
+				i++
+			} else {
+				quoted = !quoted
+			}
+		case quoted:
+			literal.WriteRune(c)
+		case isPrefix && strings.ContainsRune("#0,.", c):
+			flush()
+			return a, pattern[i:], nil
+		case !isPrefix && strings.ContainsRune("#0,.", c):
+			return nil, "", errors.Errorf("unquoted %q in the suffix", c)
+		case c == patternPercent || c == patternPerMill:
+			multiplier := 100
+			if c == patternPerMill {
+				multiplier = 1000
+			}
+			if f.multiplier != 1 && f.multiplier != multiplier {
+				return nil, "", errors.New("both percent and per-mille")
+			}
+			f.multiplier = multiplier
+			flush()
+			a = append(a, affixPart{symbol: c})
+		case c == patternMinus:
+			flush()
+			a = append(a, affixPart{symbol: c})
+		default:
+			literal.WriteRune(c)
+		}
+		i += size
+	}
+	if quoted {
+		return nil, "", errors.New("unterminated quote")
+	}
+	flush()
+	return a, "", nil
+}
+
+// numberPatternEnd returns the end of the number pattern at the beginning of pattern.
+func numberPatternEnd(pattern string) int {
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case patternDigit, patternZeroDigit, patternGroupingSep, patternDecimalSep:
+		case patternExponent:
+			j := i + 1
+			for j < len(pattern) && pattern[j] == patternZeroDigit {
+				j++
+			}
+			return j
+		default:
+			return i
+		}
+	}
+	return len(pattern)
+}
+
+// applyNumberPattern computes the digit counts of the number pattern like "#,##0.00" and "##0.###E0".
+func (f *DecimalFormat) applyNumberPattern(number string) error {
+	digitLeft, zeroDigits, digitRight := 0, 0, 0
+	groupingCount, decimalPos := -1, -1
+	for i := 0; i < len(number); i++ {
+		switch c := number[i]; c {
+		case patternDigit:
+			if zeroDigits > 0 {
+				digitRight++
+			} else {
+				digitLeft++
+			}
+			if groupingCount >= 0 && decimalPos < 0 {
+				groupingCount++
+			}
+		case patternZeroDigit:
+			if digitRight > 0 {
+				return errors.New("unexpected '0' after '#'")
+			}
+			zeroDigits++
+			if groupingCount >= 0 && decimalPos < 0 {
+				groupingCount++
+			}
+		case patternGroupingSep:
+			if decimalPos >= 0 {
+				return errors.New("grouping separator after the decimal separator")
+			}
+			groupingCount = 0
+		case patternDecimalSep:
+			if decimalPos >= 0 {
+				return errors.New("multiple decimal separators")
+			}
+			decimalPos = digitLeft + zeroDigits + digitRight
+		case patternExponent:
+			f.useExponent = true
+			f.minExpDigits = len(number) - i - 1
+			if f.minExpDigits == 0 {
+				return errors.New("missing exponent digits")
+			}
+			i = len(number)
+		}
+	}
+
+	// "###.###" is "#0.###"
+	if zeroDigits == 0 && digitLeft > 0 && decimalPos >= 0 {
+		n := decimalPos
+		if n == 0 {
+			n++
+		}
+		digitRight = digitLeft - n
+		digitLeft = n - 1
+		zeroDigits = 1
+	}
+	if (decimalPos < 0 && digitRight > 0) || (decimalPos >= 0 && (decimalPos < digitLeft || decimalPos > digitLeft+zeroDigits)) ||
+		groupingCount == 0 {
+		return errors.New("malformed number pattern")
+	}
+
+	digitTotal := digitLeft + zeroDigits + digitRight
+	effectiveDecimalPos := digitTotal
+	if decimalPos >= 0 {
+		effectiveDecimalPos = decimalPos
+		f.maxFracDigits = digitTotal - decimalPos
+		f.minFracDigits = digitLeft + zeroDigits - decimalPos
+		if f.minFracDigits < 0 {
+			f.minFracDigits = 0
+		}
+	}
+	f.minIntegerDigits = effectiveDecimalPos - digitLeft
+	f.maxIntegerDigits = patternMaxIntegerDigits
+	if f.useExponent {
+		f.maxIntegerDigits = digitLeft + f.minIntegerDigits
+	}
+	if groupingCount > 0 {
+		f.groupingSize = groupingCount
+	}
+	f.decimalSepShown = decimalPos == 0 || decimalPos == digitTotal
+	return nil
+}
+
+// Format formats d with the pattern, it returns ErrOverflow if the rounded value does not fit into Decimal.
+func (f *DecimalFormat) Format(d *Decimal) (string, error) {
+	value := d
+	if f.multiplier != 1 {
+		value = new(Decimal)
+		if err := DecimalMul(d, NewDecFromInt(int64(f.multiplier)), value); err != nil && err != ErrTruncated {
+			return "", err
+		}
+	}
+
+	var (
+		rounded  Decimal
+		exponent int
+		err      error
+	)
+	if f.useExponent {
+		exponent, err = f.roundScientific(value, &rounded)
+	} else {
+		err = value.Round(&rounded, f.maxFracDigits, f.roundMode)
+	}
+	if err != nil && err != ErrTruncated {
+		return "", err
+	}
+
+	var buf strings.Builder
+	negative := rounded.IsNegative() && !rounded.IsZero()
+	if negative {
+		buf.WriteString(f.negPrefix)
+	} else {
+		buf.WriteString(f.posPrefix)
+	}
+	f.formatDigits(&buf, &rounded, exponent)
+	if negative {
+		buf.WriteString(f.negSuffix)
+	} else {
+		buf.WriteString(f.posSuffix)
+	}
+	return buf.String(), nil
+}
+
+// roundScientific rounds d to the significant digits of the pattern into to, and returns the exponent.
+func (f *DecimalFormat) roundScientific(d *Decimal, to *Decimal) (exponent int, err error) {
+	significant := f.minIntegerDigits + f.maxFracDigits
+	if f.isEngineering() && f.minIntegerDigits == 0 {
+		significant++
+	}
+	if err = d.Round(to, significant-1-decimalExponent(d), f.roundMode); err != nil {
+		return 0, err
+	}
+	if to.IsZero() {
+		return 0, nil
+	}
+	// the rounding may carry into a new digit, e.g. 9.99 to 10.0
+	e := decimalExponent(to)
+	switch {
+	case f.isEngineering():
+		exponent = e / f.maxIntegerDigits * f.maxIntegerDigits
+		if e < 0 && e%f.maxIntegerDigits != 0 {
+			exponent -= f.maxIntegerDigits
+		}
+	default:
+		exponent = e - f.minIntegerDigits + 1
+	}
+	return exponent, nil
+}
+
+// isEngineering checks whether the exponent is a multiple of the max integer digits, e.g. "##0.###E0".
+func (f *DecimalFormat) isEngineering() bool {
+	return f.maxIntegerDigits > f.minIntegerDigits && f.maxIntegerDigits > 1
+}
+
+// decimalExponent returns e that 10^e <= |d| < 10^(e+1), it is 0 for zero.
+func decimalExponent(d *Decimal) int {
+	unscaled, scale := d.ToUnscaledBigInt()
+	if unscaled.Sign() == 0 {
+		return 0
+	}
+	return len(unscaled.Abs(unscaled).Text(10)) - 1 - int(scale)
+}
+
+// formatDigits writes the digits of the rounded d, which are shifted by exponent in the scientific notation.
+func (f *DecimalFormat) formatDigits(buf *strings.Builder, d *Decimal, exponent int) {
+	unscaled, scale := d.ToUnscaledBigInt()
+	digits := unscaled.Abs(unscaled).Text(10)
+	// the point is after pointPos digits.
+	pointPos := len(digits) - int(scale) - exponent
+	if unscaled.Sign() == 0 {
+		digits, pointPos = "", 0
+	}
+
+	var intPart, fracPart string
+	switch {
+	case pointPos <= 0:
+		fracPart = strings.Repeat("0", -pointPos) + digits
+	case pointPos >= len(digits):
+		intPart = digits + strings.Repeat("0", pointPos-len(digits))
+	default:
+		intPart, fracPart = digits[:pointPos], digits[pointPos:]
+	}
+	intPart = strings.TrimLeft(intPart, "0")
+	fracPart = strings.TrimRight(fracPart, "0")
+	if len(intPart) < f.minIntegerDigits {
+		intPart = strings.Repeat("0", f.minIntegerDigits-len(intPart)) + intPart
+	}
+	if len(fracPart) < f.minFracDigits {
+		fracPart += strings.Repeat("0", f.minFracDigits-len(fracPart))
+	}
+	if intPart == "" && fracPart == "" {
+		intPart = "0"
+	}
+
+	if f.groupingSize > 0 && !f.useExponent {
+		for i, c := range intPart {
+			if i > 0 && (len(intPart)-i)%f.groupingSize == 0 {
+				buf.WriteRune(f.symbols.GroupingSeparator)
+			}
+			buf.WriteRune(c)
+		}
+	} else {
+		buf.WriteString(intPart)
+	}
+	if fracPart != "" || f.decimalSepShown {
+		buf.WriteRune(f.symbols.DecimalSeparator)
+		buf.WriteString(fracPart)
+	}
+
+	if f.useExponent {
+		buf.WriteString(f.symbols.ExponentSeparator)
+		if exponent < 0 {
+			buf.WriteRune(f.symbols.MinusSign)
+			exponent = -exponent
+		}
+		exp := strconv.Itoa(exponent)
+		if len(exp) < f.minExpDigits {
+			buf.WriteString(strings.Repeat("0", f.minExpDigits-len(exp)))
+		}
+		buf.WriteString(exp)
+	}
+}
+
+// Parse parses the string formatted with the pattern, the grouping separators are optional.
+// It returns ErrBadNumber if str does not match the pattern.
+func (f *DecimalFormat) Parse(str string) (*Decimal, error) {
+	posMatched := strings.HasPrefix(str, f.posPrefix) && strings.HasSuffix(str, f.posSuffix) &&
+		len(str) >= len(f.posPrefix)+len(f.posSuffix)
+	negMatched := strings.HasPrefix(str, f.negPrefix) && strings.HasSuffix(str, f.negSuffix) &&
+		len(str) >= len(f.negPrefix)+len(f.negSuffix)
+	// the longer affixes win, e.g. "-1" matches both the prefix "" and "-".
+	if posMatched && negMatched {
+		negMatched = len(f.negPrefix)+len(f.negSuffix) > len(f.posPrefix)+len(f.posSuffix)
+		posMatched = !negMatched
+	}
+
+	var body string
+	switch {
+	case negMatched:
+		body = str[len(f.negPrefix) : len(str)-len(f.negSuffix)]
+	case posMatched:
+		body = str[len(f.posPrefix) : len(str)-len(f.posSuffix)]
+	default:
+		return nil, ErrBadNumber
+	}
+
+	plain, ok := f.normalize(body)
+	if !ok {
+		return nil, ErrBadNumber
+	}
+	d := new(Decimal)
+	if err := d.FromString(plain); err != nil {
+		return nil, err
+	}
+	var shift int
+	switch f.multiplier {
+	case 100:
+		shift = -2
+	case 1000:
+		shift = -3
+	}
+	if shift != 0 {
+		if err := d.Shift(shift); err != nil {
+			return nil, err
+		}
+		// Shift keeps the old resultFrac, which would round off the shifted digits in String.
+		d.resultFrac = d.digitsFrac
+	}
+	if negMatched && !d.IsZero() {
+		d = d.Neg()
+	}
+	return d, nil
+}
+
+// normalize converts the localized number like "1,234.5E-3" into the form of FromString.
+func (f *DecimalFormat) normalize(body string) (string, bool) {
+	var buf strings.Builder
+	seenDigit, seenPoint := false, false
+	for i := 0; i < len(body); {
+		c, size := utf8.DecodeRuneInString(body[i:])
+		switch {
+		case c >= '0' && c <= '9':
+			buf.WriteRune(c)
+			seenDigit = true
+		case c == f.symbols.DecimalSeparator && !seenPoint:
+			buf.WriteByte('.')
+			seenPoint = true
+		case c == f.symbols.GroupingSeparator && !seenPoint && seenDigit:
+		case f.useExponent && seenDigit && strings.HasPrefix(body[i:], f.symbols.ExponentSeparator):
+			exp := body[i+len(f.symbols.ExponentSeparator):]
+			buf.WriteByte('e')
+			if minus, size := utf8.DecodeRuneInString(exp); minus == f.symbols.MinusSign {
+				buf.WriteByte('-')
+				exp = exp[size:]
+			}
+			if exp == "" || strings.Trim(exp, "0123456789") != "" {
+				return "", false
+			}
+			buf.WriteString(exp)
+			return buf.String(), true
+		default:
+			return "", false
+		}
+		i += size
+	}
+	return buf.String(), seenDigit
+}
@@ -0,0 +1,138 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxbig
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecimalFormat(t *testing.T) {
+	tests := []struct {
+		pattern string
+		input   string
+		output  string
+	}{
+		{"#,##0.00", "1234567.891", "1,234,567.89"},
+		{"#,##0.00", "0", "0.00"},
+		{"#,##0.00", "-0.005", "0.00"},
+		{"#,##0.00", "-0.015", "-0.02"},
+		{"#,##,##0", "123456789", "123,456,789"},
+		{"#.##", "0.456", "0.46"},
+		{"#.##", "2", "2"},
+		{"#.00", "0.456", ".46"},
+		{"#,##0.", "1234", "1,234."},
+		{"000000", "1234.5", "001234"},
+		{"0.###E0", "1234", "1.234E3"},
+		{"0.###E0", "0.00012345", "1.234E-4"},
+		{"0.###E0", "-9.9999", "-1E1"},
+		{"0.###E0", "0", "0E0"},
+		{"##0.#####E0", "12345", "12.345E3"},
+		{"##0.#####E0", "123456", "123.456E3"},
+		{"##0.#####E0", "0.00123", "1.23E-3"},
+		{"##0.##E0", "12345", "12.3E3"},
+		{"00.###E0", "0.00123", "12.3E-4"},
+		{"0.00E00", "1234", "1.23E03"},
+		{"0.00E00", "0.5", "5.00E-01"},
+		{"#0.#%", "0.1234", "12.3%"},
+		{"#,##0‰", "0.5", "500‰"},
+		{"#,##0.00;(#,##0.00)", "-1234.5", "(1,234.50)"},
+		{"#,##0.00;(#,##0.00)", "1234.5", "1,234.50"},
+		{"'#'#", "12", "#12"},
+		{"# 'o''clock'", "5", "5 o'clock"},
+		{"¥#,##0", "-1000", "-¥1,000"},
+	}
+	for _, ca := range tests {
+		f, err := NewDecimalFormat(ca.pattern)
+		assert.Nil(t, err, ca.pattern)
+		output, err := f.Format(NewDecFromStringForTest(ca.input))
+		assert.Nil(t, err, ca.pattern)
+		assert.Equal(t, ca.output, output, ca.pattern+" "+ca.input)
+	}
+
+	for _, pattern := range []string{"", "abc", "#,##0.0.0", "0#.", "#,##0,", "'abc", "0;0;0", "0.0E", "#0%‰", "0 #"} {
+		_, err := NewDecimalFormat(pattern)
+		assert.NotNil(t, err, pattern)
+	}
+}
+
+func TestDecimalFormatOptions(t *testing.T) {
+	f, err := NewDecimalFormat("#,##0.00")
+	assert.Nil(t, err)
+	f.SetSymbols(DecimalFormatSymbols{
+		DecimalSeparator:  ',',
+		GroupingSeparator: '.',
+		MinusSign:         '-',
+		Percent:           '%',
+		PerMill:           '‰',
+		ExponentSeparator: "E",
+	})
+	output, err := f.Format(NewDecFromStringForTest("-1234567.891"))
+	assert.Nil(t, err)
+	assert.Equal(t, "-1.234.567,89", output)
+	d, err := f.Parse("-1.234.567,89")
+	assert.Nil(t, err)
+	assert.Equal(t, "-1234567.89", string(d.ToBytes()))
+
+	f.SetRoundMode(ModeDown)
+	f.SetGroupingSize(0)
+	output, err = f.Format(NewDecFromStringForTest("1999.999"))
+	assert.Nil(t, err)
+	assert.Equal(t, "1999,99", output)
+}
+
+func TestDecimalFormatParse(t *testing.T) {
+	tests := []struct {
+		pattern string
+		input   string
+		output  string
+	}{
+		{"#,##0.00", "1,234,567.89", "1234567.89"},
+		{"#,##0.00", "1234567.8", "1234567.8"},
+		{"#,##0.00", "-0.5", "-0.5"},
+		{"#,##0.00;(#,##0.00)", "(1,234.50)", "-1234.50"},
+		{"#0.#%", "12.3%", "0.123"},
+		{"#,##0‰", "-500‰", "-0.5"},
+		{"0.###E0", "1.234E-4", "0.0001234"},
+		{"0.###E0", "-1.5E3", "-1500"},
+		{"'#'#", "#12", "12"},
+		{"#0%", "5%", "0.05"},
+		{"#0.##%", "12.34%", "0.1234"},
+		{"#,##0%", "1,250%", "12.5"},
+	}
+	for _, ca := range tests {
+		f, err := NewDecimalFormat(ca.pattern)
+		assert.Nil(t, err, ca.pattern)
+		d, err := f.Parse(ca.input)
+		assert.Nil(t, err, ca.input)
+		if err == nil {
+			assert.Equal(t, ca.output, string(d.ToBytes()), ca.input)
+			assert.Equal(t, ca.output, d.String(), ca.input)
+		}
+	}
+
+	f, err := NewDecimalFormat("#,##0.00%")
+	assert.Nil(t, err)
+	for _, input := range []string{"", "%", "12", "12x%", ",12%", "1.2.3%", "1E5%"} {
+		_, err = f.Parse(input)
+		assert.Equal(t, ErrBadNumber, err, input)
+	}
+}
@@ -0,0 +1,98 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxbig
+
+import (
+	"strings"
+	"testing"
+)
+
+// Run the fuzz targets by
+//
+//	go test -run XXX -fuzz FuzzFromString
+//	go test -run XXX -fuzz FuzzFromBin
+
+func FuzzFromString(f *testing.F) {
+	for _, s := range []string{"0", "-0", "12.34", "-.5", "1e10", "1.5E-3", "00012.3400", "123456789012345678901234567890.123",
+		strings.Repeat("9", 90), "1E-80", "0." + strings.Repeat("0", 80) + "1", "", "-", ".", "1e", "+1"} {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		var d Decimal
+		if err := d.FromString(s); err != nil && err != ErrTruncated && err != ErrOverflow {
+			return
+		}
+		// the string of a decimal parses back to the same one.
+		str := d.String()
+		var back Decimal
+		if err := back.FromString(str); err != nil {
+			t.Fatalf("%q: FromString(%q) = %v", s, str, err)
+		}
+		if back.String() != str || back.Compare(&d) != 0 {
+			t.Fatalf("%q: %q is parsed as %q", s, str, back.String())
+		}
+		if v, scale := d.ToUnscaledBigInt(); int(scale) != int(d.GetDigitsFrac()) || (v.Sign() == 0) != d.IsZero() {
+			t.Fatalf("%q: the unscaled value %v with scale %d of %q", s, v, scale, str)
+		}
+	})
+}
+
+func FuzzFromBin(f *testing.F) {
+	for _, tt := range []struct {
+		s               string
+		precision, frac int
+	}{
+		{"-1234567890.1234", 14, 4},
+		{"0", 1, 0},
+		{"12345678901234567890123456789012345.123456789012345678901234567890", 65, 30},
+		{"-0.000001", 10, 6},
+	} {
+		d := NewDecFromStringForTest(tt.s)
+		bin, err := d.ToBin(tt.precision, tt.frac)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(bin, tt.precision, tt.frac)
+	}
+	// the short input and the digits out of range.
+	f.Add([]byte{0x80}, 32, 6)
+	f.Add([]byte{0x20}, 1, 1)
+	f.Add([]byte{0x8a}, 1, 0)
+	f.Fuzz(func(t *testing.T, bin []byte, precision, frac int) {
+		if precision <= 0 || precision > 65 || frac < 0 || frac > maxDecimalScale || frac > precision {
+			return
+		}
+		var d Decimal
+		binSize, err := d.FromBin(bin, precision, frac)
+		if err != nil {
+			return
+		}
+		if binSize != decimalBinSize(precision, frac) || binSize > len(bin) {
+			t.Fatalf("FromBin(%x, %d, %d) reads %d bytes", bin, precision, frac, binSize)
+		}
+		// a valid layout encodes back to the same value.
+		again, err := d.ToBin(precision, frac)
+		if err != nil {
+			t.Fatalf("ToBin(%s, %d, %d) = %v", d.String(), precision, frac, err)
+		}
+		var back Decimal
+		if _, err = back.FromBin(again, precision, frac); err != nil || back.Compare(&d) != 0 {
+			t.Fatalf("%s is restored as %s, %v", d.String(), back.String(), err)
+		}
+	})
+}
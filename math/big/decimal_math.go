@@ -0,0 +1,515 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxbig
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+)
+
+// The functions in this file compute with fixed-point big.Int values, a fixed-point value v
+// with w digits means v * 10^(-w). Irrational results are computed with an error bound and
+// recomputed with more digits until the bound can not change the rounding, so the results
+// are correctly rounded with every RoundMode.
+
+const (
+	// mathGuardDigits is the initial number of digits computed besides the requested scale.
+	mathGuardDigits = 10
+	// mathMaxRetries limits the times of recomputing with more digits.
+	mathMaxRetries = 16
+	// maxExpArgument is the max |x| of Exp, exp(200) does not fit into any Decimal.
+	maxExpArgument = 200
+	// maxPowExponent is the max n of Pow, the same as java.math.BigDecimal.pow.
+	maxPowExponent = 999999999
+)
+
+// Pow returns d^n exactly, 0^0 is 1 like java.math.BigDecimal.pow.
+//
+// RETURN VALUE
+//
+//	ErrBadNumber    n is negative or larger than 999999999
+//	ErrOverflow     the result does not fit into the word buffer, it is the max decimal
+//	ErrTruncated    the fraction part of the result was truncated
+func (d *Decimal) Pow(n int) (*Decimal, error) {
+	if n < 0 || n > maxPowExponent {
+		return nil, ErrBadNumber
+	}
+	unscaled, scale := d.ToUnscaledBigInt()
+	switch abs := new(big.Int).Abs(unscaled); {
+	case n == 0:
+		return NewDecFromInt(1), nil
+	case abs.Sign() == 0:
+		return NewDecFromInt(0), nil
+	case abs.Cmp(bigPow10(int(scale))) == 0:
+		// |d| is 1, skip the huge power of 10.
+		if unscaled.Sign() < 0 && n%2 == 1 {
+			return NewDecFromInt(-1), nil
+		}
+		return NewDecFromInt(1), nil
+	}
+
+	// the size of the result is checked before the power is computed.
+	maxDigits := wordBufLen * digitsPerWord
+	negative := unscaled.Sign() < 0 && n%2 == 1
+	switch e := powLog10(unscaled, int(scale), n); {
+	case e > float64(maxDigits+1):
+		return NewDecFromUnscaledBigInt(signedPow10(maxDigits+1, negative), 0)
+	case e < -float64(maxDigits+2):
+		return NewDecFromInt(0), ErrTruncated
+	}
+	if powExact(unscaled, n) {
+		unscaled.Exp(unscaled, big.NewInt(int64(n)), nil)
+		return NewDecFromUnscaledBigInt(unscaled, scale*int32(n))
+	}
+	// the power is truncated to more digits than the word buffer holds.
+	digits := maxDigits + digitsPerWord
+	p, inexact := powFixed(unscaled, int(scale), n, digits)
+	if negative {
+		p.Neg(p)
+	}
+	to, err := NewDecFromUnscaledBigInt(p, int32(digits))
+	if err == nil && inexact {
+		err = ErrTruncated
+	}
+	return to, err
+}
+
+// maxPowExactDigits limits the digits of the power computed exactly by big.Int.Exp, a power of more
+// digits is computed by powFixed to the digits of the result instead.
+const maxPowExactDigits = 4096
+
+// powExact checks whether v^|n| has at most maxPowExactDigits digits.
+func powExact(v *big.Int, n int) bool {
+	if n < 0 {
+		n = -n
+	}
+	return int64(len(new(big.Int).Abs(v).Text(10)))*int64(n) <= maxPowExactDigits
+}
+
+// powLog10 returns about log10(|v * 10^-w|^n), v must not be zero. The error is far less than 1
+// for every n up to maxPowExponent.
+func powLog10(v *big.Int, w, n int) float64 {
+	digits := new(big.Int).Abs(v).Text(10)
+	leading := digits[:myMin(len(digits), 17)]
+	f, _ := strconv.ParseFloat(leading, 64)
+	return float64(n) * (math.Log10(f) + float64(len(digits)-len(leading)-w))
+}
+
+// signedPow10 returns 10^n, negative if negative is true.
+func signedPow10(n int, negative bool) *big.Int {
+	v := bigPow10(n)
+	if negative {
+		v.Neg(v)
+	}
+	return v
+}
+
+// powFixed returns |v * 10^-w|^n truncated to a fixed-point value with w digits, and whether it is inexact.
+// v must not be zero, and n can be negative. The power is bounded with the fixed-point values of more
+// digits until both the bounds truncate to the same value, so it takes the time of the digits of the result
+// instead of v^n. The result must be less than 10^(wordBufLen*digitsPerWord+2), see powLog10.
+func powFixed(v *big.Int, w, n, digits int) (p *big.Int, inexact bool) {
+	abs := new(big.Int).Abs(v)
+	m := n
+	if m < 0 {
+		m = -m
+	}
+	// the bounds of a product of the values up to 10^83 lose about 83 more digits.
+	guard := 2*wordBufLen*digitsPerWord + mathGuardDigits
+	for retry := 0; ; retry++ {
+		prec := digits + guard*(retry+1)
+		one := bigPow10(prec)
+		// the bounds of |v * 10^-w| or its reciprocal.
+		lo, hi := new(big.Int), new(big.Int)
+		if n >= 0 {
+			quoBounds(new(big.Int).Mul(abs, one), bigPow10(w), lo, hi)
+		} else {
+			quoBounds(new(big.Int).Mul(bigPow10(w), one), abs, lo, hi)
+		}
+		plo, phi := new(big.Int).Set(one), new(big.Int).Set(one)
+		for k := m; k > 0; k >>= 1 {
+			if k&1 == 1 {
+				quoBounds(plo.Mul(plo, lo), one, plo, new(big.Int))
+				quoBounds(new(big.Int).Mul(phi, hi), one, new(big.Int), phi)
+			}
+			if k > 1 {
+				quoBounds(lo.Mul(lo, lo), one, lo, new(big.Int))
+				quoBounds(new(big.Int).Mul(hi, hi), one, new(big.Int), hi)
+			}
+		}
+
+		unit := bigPow10(prec - digits)
+		r := new(big.Int)
+		p, _ = new(big.Int).QuoRem(plo, unit, r)
+		// the bounds are the same only if every step is exact.
+		if p.Cmp(new(big.Int).Quo(phi, unit)) == 0 || retry == mathMaxRetries {
+			return p, plo.Cmp(phi) != 0 || r.Sign() != 0
+		}
+	}
+}
+
+// quoBounds sets lo and hi to x / y rounded down and up, x and y must not be negative.
+func quoBounds(x, y, lo, hi *big.Int) {
+	r := new(big.Int)
+	q, _ := new(big.Int).QuoRem(x, y, r)
+	lo.Set(q)
+	hi.Set(q)
+	if r.Sign() != 0 {
+		hi.Add(hi, bigOne)
+	}
+}
+
+// Sqrt returns the square root of d rounded to scale digits after the point with roundMode,
+// scale can be negative. It returns ErrBadNumber if d is negative.
+func (d *Decimal) Sqrt(scale int, roundMode RoundMode) (*Decimal, error) {
+	scale = myMin(scale, wordBufLen*digitsPerWord)
+	to, _, err := decimalSqrt(d, myMax(scale, 0), scaleRounder(scale, roundMode))
+	return to, err
+}
+
+// Exp returns e^d rounded to scale digits after the point with roundMode, scale can be negative.
+func (d *Decimal) Exp(scale int, roundMode RoundMode) (*Decimal, error) {
+	scale = myMin(scale, wordBufLen*digitsPerWord)
+	to, _, err := decimalExp(d, myMax(scale, 0), scaleRounder(scale, roundMode))
+	return to, err
+}
+
+// Ln returns the natural logarithm of d rounded to scale digits after the point with roundMode,
+// scale can be negative. It returns ErrBadNumber if d is not positive.
+func (d *Decimal) Ln(scale int, roundMode RoundMode) (*Decimal, error) {
+	scale = myMin(scale, wordBufLen*digitsPerWord)
+	to, _, err := decimalLn(d, myMax(scale, 0), scaleRounder(scale, roundMode))
+	return to, err
+}
+
+// Log10 returns the common logarithm of d rounded to scale digits after the point with roundMode,
+// scale can be negative. It returns ErrBadNumber if d is not positive.
+func (d *Decimal) Log10(scale int, roundMode RoundMode) (*Decimal, error) {
+	scale = myMin(scale, wordBufLen*digitsPerWord)
+	to, _, err := decimalLog10(d, myMax(scale, 0), scaleRounder(scale, roundMode))
+	return to, err
+}
+
+// rounder rounds the decimal in place, e.g. to a scale or to the precision of a Context.
+type rounder func(u *UnboundedDecimal) error
+
+// scaleRounder returns a rounder to scale digits after the point.
+func scaleRounder(scale int, roundMode RoundMode) rounder {
+	return func(u *UnboundedDecimal) error {
+		return u.Round(u, scale, roundMode)
+	}
+}
+
+// The functions below compute the results with more than minDigits digits after the point before
+// rounding, round must not keep more digits than minDigits of an inexact result.
+
+func decimalSqrt(d *Decimal, minDigits int, round rounder) (to *Decimal, inexact bool, err error) {
+	if d.IsNegative() && !d.IsZero() {
+		return nil, false, ErrBadNumber
+	}
+	unscaled, s := d.ToUnscaledBigInt()
+	// sqrt(unscaled * 10^-s) = sqrt(unscaled * 10^(2w-s)) * 10^-w
+	w := myMax(minDigits+1, (int(s)+1)/2)
+	n := unscaled.Mul(unscaled, bigPow10(2*w-int(s)))
+	root := new(big.Int).Sqrt(n)
+	sticky := new(big.Int).Mul(root, root).Cmp(n)
+	if sticky == 0 {
+		// an exact root prefers half the scale of d.
+		root, w = stripFixedZeros(root, w, (int(s)+1)/2)
+	}
+	return roundFixed(root, w, -sticky, round)
+}
+
+func decimalExp(d *Decimal, minDigits int, round rounder) (to *Decimal, inexact bool, err error) {
+	if d.IsZero() {
+		return roundFixed(big.NewInt(1), 0, 0, round)
+	}
+	x := NewUnboundedDecFromDecimal(d)
+	if x.Compare(NewUnboundedDecFromInt(maxExpArgument)) > 0 {
+		to = new(Decimal)
+		maxDecimal(wordBufLen*digitsPerWord, 0, to)
+		to.updateJavaValue()
+		return to, true, ErrOverflow
+	}
+	if x.Compare(NewUnboundedDecFromInt(-maxExpArgument)) < 0 {
+		// e^d < 10^-86, it is between 0 and one unit of any scale a Decimal can hold.
+		to, _, err = roundBounds(new(big.Int), big.NewInt(1), minDigits+1, round)
+		return to, true, err
+	}
+	to, err = approximate(minDigits, round, func(w int) (v, e *big.Int) {
+		return fixedExp(x, w)
+	})
+	return to, true, err
+}
+
+func decimalLn(d *Decimal, minDigits int, round rounder) (to *Decimal, inexact bool, err error) {
+	if d.IsNegative() || d.IsZero() {
+		return nil, false, ErrBadNumber
+	}
+	if d.Compare(NewDecFromInt(1)) == 0 {
+		return roundFixed(new(big.Int), 0, 0, round)
+	}
+	x := NewUnboundedDecFromDecimal(d)
+	to, err = approximate(minDigits, round, func(w int) (v, e *big.Int) {
+		return fixedLn(x, w)
+	})
+	return to, true, err
+}
+
+func decimalLog10(d *Decimal, minDigits int, round rounder) (to *Decimal, inexact bool, err error) {
+	if d.IsNegative() || d.IsZero() {
+		return nil, false, ErrBadNumber
+	}
+	x := NewUnboundedDecFromDecimal(d)
+	if mantissa, exponent := x.mantissaExponent(); mantissa.Compare(NewUnboundedDecFromInt(1)) == 0 {
+		// the only rational results are of the powers of 10.
+		return roundFixed(big.NewInt(int64(exponent)), 0, 0, round)
+	}
+	to, err = approximate(minDigits, round, func(w int) (v, e *big.Int) {
+		// ln(x) / ln(10)
+		ln, lnErr := fixedLn(x, w)
+		ln10, ln10Err := fixedLn10(w)
+		v = fixedDiv(ln, ln10, w)
+		// |v| < 100, so the error is at most lnErr + 100 * ln10Err plus the truncation.
+		e = new(big.Int).Mul(ln10Err, big.NewInt(100))
+		e.Add(e, lnErr)
+		e.Add(e, bigOne)
+		return
+	})
+	return to, true, err
+}
+
+// approximate calls f with more and more digits until the error bound of its result can not
+// change the rounding. f returns the fixed-point value with w digits and its error bound.
+func approximate(minDigits int, round rounder, f func(w int) (v, e *big.Int)) (*Decimal, error) {
+	guard := mathGuardDigits
+	for i := 0; ; i++ {
+		w := minDigits + guard
+		v, e := f(w)
+		lo := new(big.Int).Sub(v, e)
+		hi := new(big.Int).Add(v, e)
+		to, ok, err := roundBounds(lo, hi, w, round)
+		if ok || i == mathMaxRetries {
+			return to, err
+		}
+		guard *= 2
+	}
+}
+
+// roundBounds rounds a value strictly between lo and hi with w digits,
+// ok is false if the rounding of lo and hi are not the same.
+func roundBounds(lo, hi *big.Int, w int, round rounder) (to *Decimal, ok bool, err error) {
+	// the value is larger than lo and less than hi, the appended digit keeps them inexact.
+	var low, high UnboundedDecimal
+	low.value.Add(lo.Mul(lo, bigTen), bigOne)
+	low.digitsFrac = w + 1
+	high.value.Sub(hi.Mul(hi, bigTen), bigOne)
+	high.digitsFrac = w + 1
+	if err = round(&low); err != nil {
+		return nil, true, err
+	}
+	if err = round(&high); err != nil {
+		return nil, true, err
+	}
+	to, err = low.ToDecimal()
+	return to, low.Compare(&high) == 0, err
+}
+
+// roundFixed rounds the fixed-point value v with w digits, the value is exact if sticky is 0,
+// or a bit larger than v if sticky is 1, or a bit less than v if sticky is -1.
+// inexact reports whether the result is not the value.
+func roundFixed(v *big.Int, w int, sticky int, round rounder) (to *Decimal, inexact bool, err error) {
+	var u, rounded UnboundedDecimal
+	u.value.Set(v)
+	u.digitsFrac = w
+	if sticky != 0 {
+		u.value.Mul(&u.value, bigTen)
+		u.value.Add(&u.value, big.NewInt(int64(sticky)))
+		u.digitsFrac++
+	}
+	rounded.Set(&u)
+	if err = round(&rounded); err != nil {
+		return nil, false, err
+	}
+	to, err = rounded.ToDecimal()
+	return to, rounded.Compare(&u) != 0, err
+}
+
+// stripFixedZeros removes the trailing zeros of the fixed-point value v with w digits, but keeps minDigits digits.
+func stripFixedZeros(v *big.Int, w, minDigits int) (*big.Int, int) {
+	q, r := new(big.Int), new(big.Int)
+	for w > minDigits {
+		if q.QuoRem(v, bigTen, r); r.Sign() != 0 {
+			break
+		}
+		v, q = q, v
+		w--
+	}
+	return v, w
+}
+
+// mantissaExponent returns m and e that d = m * 10^e and 1 <= m < 10, d must be positive.
+func (d *UnboundedDecimal) mantissaExponent() (mantissa *UnboundedDecimal, exponent int) {
+	n := len(d.value.String())
+	exponent = n - 1 - d.digitsFrac
+	mantissa = new(UnboundedDecimal).Set(d)
+	mantissa.digitsFrac = n - 1
+	return mantissa.stripTrailingZeros(), exponent
+}
+
+// stripTrailingZeros removes the trailing zeros after the point.
+func (d *UnboundedDecimal) stripTrailingZeros() *UnboundedDecimal {
+	r := new(big.Int)
+	for d.digitsFrac > 0 {
+		q, _ := new(big.Int).QuoRem(&d.value, bigTen, r)
+		if r.Sign() != 0 {
+			break
+		}
+		d.value.Set(q)
+		d.digitsFrac--
+	}
+	return d
+}
+
+// toFixed returns d as a fixed-point value with w digits, truncated.
+func (d *UnboundedDecimal) toFixed(w int) *big.Int {
+	if w >= d.digitsFrac {
+		return new(big.Int).Mul(&d.value, bigPow10(w-d.digitsFrac))
+	}
+	return new(big.Int).Quo(&d.value, bigPow10(d.digitsFrac-w))
+}
+
+// fixedMul returns a * b of fixed-point values with w digits, truncated.
+func fixedMul(a, b *big.Int, w int) *big.Int {
+	v := new(big.Int).Mul(a, b)
+	return v.Quo(v, bigPow10(w))
+}
+
+// fixedDiv returns a / b of fixed-point values with w digits, truncated.
+func fixedDiv(a, b *big.Int, w int) *big.Int {
+	v := new(big.Int).Mul(a, bigPow10(w))
+	return v.Quo(v, b)
+}
+
+// fixedExp returns e^x as a fixed-point value with w digits and its error bound, |x| <= maxExpArgument.
+func fixedExp(x *UnboundedDecimal, w int) (v, e *big.Int) {
+	// e^x = (e^(x/2^k))^(2^k), |x/2^k| <= 1 makes the taylor series converge fast.
+	abs := new(UnboundedDecimal).Set(x)
+	abs.value.Abs(&abs.value)
+	k := 0
+	for limit := NewUnboundedDecFromInt(1); abs.Compare(limit) > 0; k++ {
+		_ = UnboundedDecimalMul(limit, NewUnboundedDecFromInt(2), limit)
+	}
+	// the squaring multiplies the relative error of the series by 2^k, and e^|x| has
+	// |x|/ln(10) integer digits at most, so the series is computed with more digits.
+	absInt, _ := abs.ToInt()
+	ww := w + k/3 + int(absInt)*4/9 + 8
+	one := bigPow10(ww)
+	r := x.toFixed(ww)
+	r.Abs(r)
+	r.Rsh(r, uint(k))
+
+	// taylor series: sum(r^n / n!)
+	sum := new(big.Int).Set(one)
+	term := new(big.Int).Set(one)
+	for n := int64(1); term.Sign() != 0; n++ {
+		term = fixedMul(term, r, ww)
+		term.Quo(term, big.NewInt(n))
+		sum.Add(sum, term)
+	}
+	for ; k > 0; k-- {
+		sum = fixedMul(sum, sum, ww)
+	}
+	if x.IsNegative() {
+		sum = fixedDiv(one, sum, ww)
+	}
+	// back to w digits, the error of ww digits is far less than one unit of w digits.
+	v = sum.Quo(sum, bigPow10(ww-w))
+	return v, big.NewInt(2)
+}
+
+// fixedAtanh returns atanh(y) of the fixed-point value y with w digits and its error bound, |y| <= 1/3.
+func fixedAtanh(y *big.Int, w int) (v, e *big.Int) {
+	// taylor series: sum(y^(2n+1) / (2n+1))
+	sum := new(big.Int).Set(y)
+	y2 := fixedMul(y, y, w)
+	power := new(big.Int).Set(y)
+	terms := int64(1)
+	for n := int64(3); power.Sign() != 0; n += 2 {
+		power = fixedMul(power, y2, w)
+		sum.Add(sum, new(big.Int).Quo(power, big.NewInt(n)))
+		terms++
+	}
+	// every term is truncated at most by 2 units.
+	return sum, big.NewInt(2*terms + 2)
+}
+
+// fixedLn2 returns ln(2) = 2 * atanh(1/3) as a fixed-point value with w digits and its error bound.
+func fixedLn2(w int) (v, e *big.Int) {
+	v, e = fixedAtanh(new(big.Int).Quo(bigPow10(w), big.NewInt(3)), w)
+	return v.Lsh(v, 1), e.Lsh(e, 1)
+}
+
+// fixedLn10 returns ln(10) = 3 * ln(2) + 2 * atanh(1/9) as a fixed-point value with w digits and its error bound.
+func fixedLn10(w int) (v, e *big.Int) {
+	ln2, ln2Err := fixedLn2(w)
+	v, e = fixedAtanh(new(big.Int).Quo(bigPow10(w), big.NewInt(9)), w)
+	v.Lsh(v, 1)
+	v.Add(v, ln2.Mul(ln2, big.NewInt(3)))
+	e.Lsh(e, 1)
+	e.Add(e, ln2Err.Mul(ln2Err, big.NewInt(3)))
+	return v, e
+}
+
+// fixedLn returns ln(x) as a fixed-point value with w digits and its error bound, x must be positive.
+func fixedLn(x *UnboundedDecimal, w int) (v, e *big.Int) {
+	// ln(x) = ln(m * 2^j * 10^exponent) = 2 * atanh((m-1)/(m+1)) + j * ln(2) + exponent * ln(10),
+	// where 0.75 <= m < 1.5.
+	mantissa, exponent := x.mantissaExponent()
+	ww := w + 4
+	one := bigPow10(ww)
+	m := mantissa.toFixed(ww)
+	threshold := new(big.Int).Quo(new(big.Int).Mul(one, big.NewInt(3)), big.NewInt(2))
+	j := int64(0)
+	for m.Cmp(threshold) >= 0 {
+		m.Rsh(m, 1)
+		j++
+	}
+	y := fixedDiv(new(big.Int).Sub(m, one), new(big.Int).Add(m, one), ww)
+	v, e = fixedAtanh(y, ww)
+	v.Lsh(v, 1)
+	e.Lsh(e, 1)
+	// the halving of m and the division for y are truncated.
+	e.Add(e, big.NewInt(2*j+4))
+
+	ln2, ln2Err := fixedLn2(ww)
+	v.Add(v, ln2.Mul(ln2, big.NewInt(j)))
+	e.Add(e, ln2Err.Mul(ln2Err, big.NewInt(j)))
+	if exponent != 0 {
+		ln10, ln10Err := fixedLn10(ww)
+		exp := big.NewInt(int64(exponent))
+		v.Add(v, ln10.Mul(ln10, exp))
+		e.Add(e, ln10Err.Mul(ln10Err, exp.Abs(exp)))
+	}
+
+	// back to w digits.
+	v.Quo(v, bigPow10(ww-w))
+	e.Quo(e, bigPow10(ww-w))
+	return v, e.Add(e, big.NewInt(2))
+}
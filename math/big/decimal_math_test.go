@@ -0,0 +1,130 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxbig
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecimalPow(t *testing.T) {
+	tests := []struct {
+		input  string
+		n      int
+		output string
+		err    error
+	}{
+		{"2", 10, "1024", nil},
+		{"-1.5", 3, "-3.375", nil},
+		{"1.1", 40, "45.2592555681759518058893560348969204658401", nil},
+		{"0", 0, "1", nil},
+		{"0.00", 5, "0", nil},
+		{"-1.00", 999999999, "-1", nil},
+		{"10", 81, "999999999999999999999999999999999999999999999999999999999999999999999999999999999", ErrOverflow},
+		{"2", -1, "", ErrBadNumber},
+		// the size of the huge powers is bounded before computing them.
+		{"2", 999999999, "999999999999999999999999999999999999999999999999999999999999999999999999999999999", ErrOverflow},
+		{"-2", 999999999, "-999999999999999999999999999999999999999999999999999999999999999999999999999999999", ErrOverflow},
+		{"-2", 999999998, "999999999999999999999999999999999999999999999999999999999999999999999999999999999", ErrOverflow},
+		{"0.5", 999999999, "0", ErrTruncated},
+		{"0.999", 2000, "0.135199925397499679150002368360265054882910092339288625418960311046672596854967942", ErrTruncated},
+		{"1.00000001", 999999999, "22026.464473218816919670532986039239772346150831681743222501656196928672534038", ErrTruncated},
+		{"-1.00000001", 999999999, "-22026.464473218816919670532986039239772346150831681743222501656196928672534038", ErrTruncated},
+		{"0.5", 100, "0.000000000000000000000000000000788860905221011805411728565282786229673206435109023", ErrTruncated},
+		{"0.5", 5000, "0", ErrTruncated},
+	}
+	for _, ca := range tests {
+		result, err := NewDecFromStringForTest(ca.input).Pow(ca.n)
+		assert.Equal(t, ca.err, err, ca.input)
+		if result != nil {
+			assert.Equal(t, ca.output, string(result.ToBytes()), ca.input)
+		}
+	}
+}
+
+func TestDecimalSqrt(t *testing.T) {
+	tests := []struct {
+		input  string
+		scale  int
+		mode   RoundMode
+		output string
+		err    error
+	}{
+		{"2", 20, ModeHalfUp, "1.41421356237309504880", nil},
+		{"2", 5, ModeDown, "1.41421", nil},
+		{"2", 5, ModeUp, "1.41422", nil},
+		{"0.0002", 10, ModeHalfToEven, "0.0141421356", nil},
+		{"1.21", 3, ModeUnnecessary, "1.100", nil},
+		{"1.21", 0, ModeUnnecessary, "", ErrRoundingNecessary},
+		{"0", 2, ModeHalfUp, "0.00", nil},
+		{"-4", 2, ModeHalfUp, "", ErrBadNumber},
+	}
+	for _, ca := range tests {
+		result, err := NewDecFromStringForTest(ca.input).Sqrt(ca.scale, ca.mode)
+		assert.Equal(t, ca.err, err, ca.input)
+		if err == nil {
+			assert.Equal(t, ca.output, string(result.ToBytes()), ca.input)
+		}
+	}
+}
+
+func TestDecimalExpAndLog(t *testing.T) {
+	tests := []struct {
+		fn     func(d *Decimal, scale int, mode RoundMode) (*Decimal, error)
+		input  string
+		scale  int
+		mode   RoundMode
+		output string
+		err    error
+	}{
+		{(*Decimal).Exp, "1", 30, ModeHalfToEven, "2.718281828459045235360287471353", nil},
+		{(*Decimal).Exp, "-1", 10, ModeUp, "0.3678794412", nil},
+		{(*Decimal).Exp, "0.1", 40, ModeHalfUp, "1.1051709180756476248117078264902466682245", nil},
+		{(*Decimal).Exp, "100", 0, ModeHalfUp, "26881171418161354484126255515800135873611119", nil},
+		{(*Decimal).Exp, "-50", 30, ModeHalfUp, "0.000000000000000000000192874985", nil},
+		{(*Decimal).Exp, "12.5", -2, ModeHalfUp, "268300", nil},
+		{(*Decimal).Exp, "0", 2, ModeUnnecessary, "1.00", nil},
+		{(*Decimal).Exp, "-1000", 5, ModeCeiling, "0.00001", nil},
+		{(*Decimal).Exp, "-1000", 5, ModeFloor, "0.00000", nil},
+		{(*Decimal).Exp, "1", 5, ModeUnnecessary, "", ErrRoundingNecessary},
+		{(*Decimal).Exp, "1000", 5, ModeHalfUp, "", ErrOverflow},
+		{(*Decimal).Ln, "10", 30, ModeHalfUp, "2.302585092994045684017991454684", nil},
+		{(*Decimal).Ln, "0.5", 20, ModeFloor, "-0.69314718055994530942", nil},
+		{(*Decimal).Ln, "0.0000000000000000000000000000000000000001", 10, ModeCeiling, "-92.1034037197", nil},
+		{(*Decimal).Ln, "0.00000000000000000000000000000000000000000000000005", 40, ModeHalfUp,
+			"-113.5198167372681838262988134009920227405295", nil},
+		{(*Decimal).Ln, "1.000", 3, ModeUnnecessary, "0.000", nil},
+		{(*Decimal).Ln, "0", 3, ModeHalfUp, "", ErrBadNumber},
+		{(*Decimal).Ln, "-1", 3, ModeHalfUp, "", ErrBadNumber},
+		{(*Decimal).Log10, "2", 25, ModeHalfUp, "0.3010299956639811952137389", nil},
+		{(*Decimal).Log10, "123.456", 15, ModeDown, "2.091512201627771", nil},
+		{(*Decimal).Log10, "0.001", 3, ModeUnnecessary, "-3.000", nil},
+		{(*Decimal).Log10, "1000", 0, ModeUnnecessary, "3", nil},
+		{(*Decimal).Log10, "0", 3, ModeHalfUp, "", ErrBadNumber},
+	}
+	for _, ca := range tests {
+		result, err := ca.fn(NewDecFromStringForTest(ca.input), ca.scale, ca.mode)
+		assert.Equal(t, ca.err, err, ca.input)
+		if err == nil {
+			assert.Equal(t, ca.output, string(result.ToBytes()), ca.input)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxbig
+
+// The methods in this file follow the ones of java.math.BigDecimal of the same names. As the scale
+// of a Decimal is never negative, where BigDecimal would return a negative scale, e.g. 1200 stripped
+// of the trailing zeros is 1.2E+3 of scale -3, the scale is 0 instead.
+// Like the methods in decimal_arith.go, the receiver is never modified.
+
+// Scale returns the number of digits after the point.
+func (d *Decimal) Scale() int {
+	return int(d.digitsFrac)
+}
+
+// Precision returns the number of digits of the unscaled value, e.g. 5 of 123.45 and 3 of 0.00120,
+// and 1 of zero.
+func (d *Decimal) Precision() int {
+	precision := 0
+	for _, c := range d.ToBytes() {
+		if c >= '1' && c <= '9' || c == '0' && precision > 0 {
+			precision++
+		}
+	}
+	if precision == 0 {
+		return 1
+	}
+	return precision
+}
+
+// Signum returns -1, 0 or 1 as d is negative, zero or positive.
+func (d *Decimal) Signum() int {
+	switch {
+	case d.IsZero():
+		return 0
+	case d.negative:
+		return -1
+	}
+	return 1
+}
+
+// SetScale returns d with scale digits after the point, d is rounded with roundMode if scale is less
+// than the scale of d. With ModeUnnecessary, it returns ErrRoundingNecessary if d would be rounded.
+// A negative scale rounds d to 10^-scale, e.g. 1234 to scale -2 is 1200.
+func (d *Decimal) SetScale(scale int, roundMode RoundMode) (*Decimal, error) {
+	to := new(Decimal)
+	err := d.Round(to, scale, roundMode)
+	return to, err
+}
+
+// StripTrailingZeros returns d without the trailing zeros after the point, e.g. 1.2300 is 1.23, and 1200.00
+// is 1200. Zero is 0.
+func (d *Decimal) StripTrailingZeros() *Decimal {
+	if d.IsZero() {
+		to := zeroBigDecimal
+		to.updateJavaValue()
+		return &to
+	}
+	to := *d
+	_, digitsFrac := d.removeTrailingZeros()
+	to.digitsFrac = int8(digitsFrac)
+	to.resultFrac = to.digitsFrac
+	to.updateJavaValue()
+	return &to
+}
+
+// Ulp returns the unit in the last place of d, i.e. 10^-scale, e.g. 0.01 of 123.45 and 1 of 123.
+func (d *Decimal) Ulp() *Decimal {
+	to := new(Decimal)
+	_ = to.FromUnscaledBigInt(bigOne, int32(d.digitsFrac))
+	return to
+}
+
+// MovePointLeft returns d * 10^-n with the scale max(scale + n, 0), e.g. 123.4 moved left by 2 is 1.234.
+// n can be negative. It returns ErrOverflow or ErrTruncated if the digits do not fit into the word buffer.
+func (d *Decimal) MovePointLeft(n int) (*Decimal, error) {
+	return d.movePoint(-n)
+}
+
+// MovePointRight returns d * 10^n with the scale max(scale - n, 0), e.g. 1.5 moved right by 3 is 1500.
+// n can be negative. It returns ErrOverflow or ErrTruncated if the digits do not fit into the word buffer.
+func (d *Decimal) MovePointRight(n int) (*Decimal, error) {
+	return d.movePoint(n)
+}
+
+func (d *Decimal) movePoint(n int) (*Decimal, error) {
+	if n < -maxPowExponent || n > maxPowExponent {
+		return nil, ErrBadNumber
+	}
+	scale := myMax(int(d.digitsFrac)-n, 0)
+	to := *d
+	// Shift drops the trailing zeros, they are padded back by the rounding.
+	if err := to.Shift(n); err != nil {
+		to.resultFrac = to.digitsFrac
+		to.updateJavaValue()
+		return &to, err
+	}
+	err := to.Round(&to, scale, ModeUnnecessary)
+	return &to, err
+}
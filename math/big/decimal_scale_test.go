@@ -0,0 +1,117 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxbig
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScaleAndPrecision(t *testing.T) {
+	tests := []struct {
+		input     string
+		scale     int
+		precision int
+		signum    int
+		ulp       string
+		stripped  string
+	}{
+		{"0", 0, 1, 0, "1", "0"},
+		{"-0.000", 3, 1, 0, "0.001", "0"},
+		{"123.45", 2, 5, 1, "0.01", "123.45"},
+		{"-123.4500", 4, 7, -1, "0.0001", "-123.45"},
+		{"0.00120", 5, 3, 1, "0.00001", "0.0012"},
+		{"1200.00", 2, 6, 1, "0.01", "1200"},
+		{"1000000000.000000000", 9, 19, 1, "0.000000001", "1000000000"},
+		{"-7", 0, 1, -1, "1", "-7"},
+	}
+	for _, tt := range tests {
+		d := NewDecFromStringForTest(tt.input)
+		str := d.String()
+		assert.Equal(t, tt.scale, d.Scale(), tt.input)
+		assert.Equal(t, tt.precision, d.Precision(), tt.input)
+		assert.Equal(t, tt.signum, d.Signum(), tt.input)
+		assert.Equal(t, tt.ulp, d.Ulp().String(), tt.input)
+		stripped := d.StripTrailingZeros()
+		assert.Equal(t, tt.stripped, stripped.String(), tt.input)
+		assert.Equal(t, tt.stripped, stripped.JavaValue, tt.input)
+		assert.Equal(t, str, d.String())
+	}
+}
+
+func TestSetScale(t *testing.T) {
+	tests := []struct {
+		input  string
+		scale  int
+		mode   RoundMode
+		output string
+		err    error
+	}{
+		{"1.5", 3, ModeUnnecessary, "1.500", nil},
+		{"1.2345", 2, ModeHalfUp, "1.23", nil},
+		{"-1.235", 2, ModeHalfToEven, "-1.24", nil},
+		{"1.201", 2, ModeCeiling, "1.21", nil},
+		{"1.201", 2, ModeUnnecessary, "", ErrRoundingNecessary},
+		{"1.200", 1, ModeUnnecessary, "1.2", nil},
+		{"1234", -2, ModeHalfUp, "1200", nil},
+	}
+	for _, tt := range tests {
+		d := NewDecFromStringForTest(tt.input)
+		to, err := d.SetScale(tt.scale, tt.mode)
+		assert.Equal(t, tt.err, err, tt.input)
+		if err == nil {
+			assert.Equal(t, tt.output, to.String(), tt.input)
+		}
+		assert.Equal(t, tt.input, d.String())
+	}
+}
+
+func TestMovePoint(t *testing.T) {
+	tests := []struct {
+		input string
+		n     int
+		left  string
+		right string
+	}{
+		{"123.4", 2, "1.234", "12340"},
+		{"1.5", 3, "0.0015", "1500"},
+		{"1.2300", 1, "0.12300", "12.300"},
+		{"-0.05", 1, "-0.005", "-0.5"},
+		{"120", -1, "1200", "12.0"},
+		{"0", 2, "0.00", "0"},
+		{"1.5", 0, "1.5", "1.5"},
+	}
+	for _, tt := range tests {
+		d := NewDecFromStringForTest(tt.input)
+		left, err := d.MovePointLeft(tt.n)
+		assert.NoError(t, err, tt.input)
+		assert.Equal(t, tt.left, left.String(), tt.input)
+		right, err := d.MovePointRight(tt.n)
+		assert.NoError(t, err, tt.input)
+		assert.Equal(t, tt.right, right.String(), tt.input)
+		assert.Equal(t, tt.input, d.String())
+	}
+
+	_, err := NewDecFromStringForTest("1").MovePointRight(90)
+	assert.Equal(t, ErrOverflow, err)
+	_, err = NewDecFromStringForTest("1").MovePointLeft(1 << 40)
+	assert.Equal(t, ErrBadNumber, err)
+}
@@ -18,6 +18,9 @@
 package gxbig
 
 import (
+	"bytes"
+	"math"
+	"math/big"
 	"strings"
 	"testing"
 )
@@ -146,6 +149,82 @@ func TestToFloat(t *testing.T) {
 	}
 }
 
+func TestFloatConversion(t *testing.T) {
+	tests := []struct {
+		f        float64
+		shortest string
+		exact    string
+	}{
+		{0.1, "0.1", "0.1000000000000000055511151231257827021181583404541015625"},
+		{-2.5, "-2.5", "-2.5"},
+		{1e20, "100000000000000000000", "100000000000000000000"},
+		{0, "0", "0"},
+		{1.0 / 3, "0.3333333333333333", "0.333333333333333314829616256247390992939472198486328125"},
+	}
+	for _, ca := range tests {
+		var shortest, exact Decimal
+		assert.Nil(t, shortest.FromFloat64(ca.f))
+		assert.Equal(t, ca.shortest, string(shortest.ToBytes()))
+		assert.Nil(t, exact.FromFloat64Exact(ca.f))
+		assert.Equal(t, ca.exact, string(exact.ToBytes()))
+
+		f, isExact := exact.ToFloat64Exact()
+		assert.Equal(t, ca.f, f)
+		assert.True(t, isExact)
+		f, isExact = shortest.ToFloat64Exact()
+		assert.Equal(t, ca.f, f)
+		assert.Equal(t, ca.shortest == ca.exact, isExact)
+	}
+
+	var dec Decimal
+	assert.Nil(t, dec.FromFloat32(0.1))
+	assert.Equal(t, "0.1", string(dec.ToBytes()))
+	f32, exact := dec.ToFloat32Exact()
+	assert.Equal(t, float32(0.1), f32)
+	assert.False(t, exact)
+	dec = zeroBigDecimal
+	assert.Nil(t, dec.FromFloat32Exact(0.1))
+	assert.Equal(t, "0.100000001490116119384765625", string(dec.ToBytes()))
+	f32, exact = dec.ToFloat32Exact()
+	assert.Equal(t, float32(0.1), f32)
+	assert.True(t, exact)
+
+	assert.Equal(t, ErrBadNumber, dec.FromFloat64(math.NaN()))
+	assert.Equal(t, ErrBadNumber, dec.FromFloat64Exact(math.Inf(-1)))
+	assert.Equal(t, ErrTruncated, dec.FromFloat64Exact(1e-100))
+	assert.Equal(t, ErrOverflow, dec.FromFloat64Exact(1e100))
+
+	// the values beyond the word buffer, the max decimal on overflow, and the digits that fit on truncation.
+	assert.Equal(t, ErrOverflow, dec.FromFloat64Exact(1e308))
+	assert.Equal(t, strings.Repeat("9", 81), dec.String())
+	assert.Equal(t, ErrOverflow, dec.FromFloat64Exact(-1e308))
+	assert.Equal(t, "-"+strings.Repeat("9", 81), dec.String())
+	assert.Equal(t, ErrTruncated, dec.FromFloat64Exact(1e-300))
+	assert.True(t, dec.IsZero())
+	assert.Equal(t, ErrTruncated, dec.FromFloat64Exact(1.5e-20))
+	assert.Equal(t, "0.000000000000000000015000000000000001434248225391935590075916707008522588118453605", dec.String())
+
+	_, err := NewDecFromStringForTest("1e50").ToFloat32()
+	assert.Equal(t, ErrOverflow, err)
+	f64, err := NewDecFromStringForTest("1e50").ToFloat64()
+	assert.Nil(t, err)
+	assert.Equal(t, 1e50, f64)
+
+	// ToFloat64 converts String, which is rounded to the result frac, ToFloat64Exact all the digits.
+	var quo Decimal
+	assert.Nil(t, DecimalDiv(NewDecFromInt(2), NewDecFromInt(3), &quo, DivFracIncr))
+	assert.Equal(t, "0.6667", quo.String())
+	f64, err = quo.ToFloat64()
+	assert.Nil(t, err)
+	assert.Equal(t, 0.6667, f64)
+	f64, isExact := quo.ToFloat64Exact()
+	assert.Equal(t, 0.666666666, f64)
+	assert.False(t, isExact)
+	f32, err = quo.ToFloat32()
+	assert.Nil(t, err)
+	assert.Equal(t, float32(0.6667), f32)
+}
+
 func TestToHashKey(t *testing.T) {
 	tests := []struct {
 		numbers []string
@@ -218,6 +297,80 @@ func TestToHashKey(t *testing.T) {
 			assert.Equal(t, keys[0], keys[i])
 		}
 	}
+
+	// the trailing zeros beyond the max frac are removed, but the non-zero digits are not truncated silently.
+	var dec Decimal
+	assert.Nil(t, dec.FromString("1.5"+strings.Repeat("0", 40)))
+	key, err := dec.ToHashKey()
+	assert.Nil(t, err)
+	expected, err := NewDecFromStringForTest("1.5").ToHashKey()
+	assert.Nil(t, err)
+	assert.Equal(t, expected, key)
+	assert.Nil(t, dec.FromString("0."+strings.Repeat("1", 31)))
+	_, err = dec.ToHashKey()
+	assert.Equal(t, ErrBadNumber, err)
+}
+
+func TestOrderedKey(t *testing.T) {
+	keyTests := []struct {
+		input  string
+		key    []byte
+		output string
+	}{
+		{"0", []byte{0x02}, "0"},
+		{"-0.000", []byte{0x02}, "0"},
+		{"12.5", []byte{0x03, 0x82, 0x19, 0x64}, "12.5"},
+		{"-12.5", []byte{0x01, 0x7d, 0xe6, 0x9b}, "-12.5"},
+		{"12.50", []byte{0x03, 0x82, 0x19, 0x64}, "12.5"},
+		{"0.001", []byte{0x03, 0x7e, 0x14}, "0.001"},
+		{"100", []byte{0x03, 0x83, 0x14}, "100"},
+	}
+	for _, tt := range keyTests {
+		var dec Decimal
+		assert.NoError(t, dec.FromString(tt.input))
+		key := dec.ToOrderedKey()
+		assert.Equal(t, tt.key, key, tt.input)
+		keySize, err := dec.FromOrderedKey(key)
+		assert.NoError(t, err)
+		assert.Equal(t, len(key), keySize)
+		assert.Equal(t, tt.output, dec.String())
+	}
+
+	numbers := []string{
+		"-999999999999999999999999999999999999999999999.999999999999999999999999999999",
+		"-100000000000", "-99999999999.9", "-12.5", "-12.499", "-12.49", "-12.4", "-12", "-1.01", "-1", "-0.99",
+		"-0.1", "-0.01", "-0.000000000000000000000000000001", "0",
+		"0.000000000000000000000000000001", "0.01", "0.010000001", "0.1", "0.99", "1", "1.000000001",
+		"1.01", "9", "10", "10.5", "12", "12.4", "12.49", "12.499", "12.5", "99999999999.9", "100000000000",
+		"999999999999999999999999999999999999999999999.999999999999999999999999999999",
+	}
+	decs := make([]*Decimal, len(numbers))
+	keys := make([][]byte, len(numbers))
+	for i, num := range numbers {
+		decs[i] = new(Decimal)
+		assert.NoError(t, decs[i].FromString(num))
+		keys[i] = decs[i].ToOrderedKey()
+	}
+	for i := range decs {
+		for j := range decs {
+			assert.Equal(t, decs[i].Compare(decs[j]), bytes.Compare(keys[i], keys[j]), "%s <> %s", numbers[i], numbers[j])
+		}
+
+		// the key is followed by another field.
+		var dec Decimal
+		keySize, err := dec.FromOrderedKey(append(append([]byte{}, keys[i]...), 0x00, 0xff))
+		assert.NoError(t, err)
+		assert.Equal(t, len(keys[i]), keySize)
+		assert.Equal(t, 0, dec.Compare(decs[i]), numbers[i])
+		assert.Equal(t, keys[i], dec.ToOrderedKey(), numbers[i])
+	}
+
+	for _, key := range [][]byte{nil, {0x00}, {0x04}, {0x03}, {0x03, 0x82}, {0x03, 0x82, 0x19}, {0x03, 0x82, 0xc8},
+		{0x03, 0x82, 0x00}, {0x03, 0x82, 0x03, 0x14}, {0x01, 0x7d, 0xe6}} {
+		var dec Decimal
+		_, err := dec.FromOrderedKey(key)
+		assert.Equal(t, ErrBadNumber, err, "%x", key)
+	}
 }
 
 func TestRemoveTrailingZeros(t *testing.T) {
@@ -390,6 +543,9 @@ func TestRoundWithHalfEven(t *testing.T) {
 		{"5.4", -1, "10", nil},
 		{".999", 0, "1", nil},
 		{"999999999", -9, "1000000000", nil},
+		{"2.5", 0, "3", nil},
+		{"-2.5", 0, "-3", nil},
+		{"0.125", 2, "0.13", nil},
 	}
 
 	for _, ca := range tests {
@@ -448,7 +604,43 @@ func TestRoundWithCeil(t *testing.T) {
 		{"15.1", 0, "16", nil},
 		{"15.5", 0, "16", nil},
 		{"15.9", 0, "16", nil},
-		//TODO:fix me
+		// the old unexported modeCeiling rounded the negative values away from zero like ModeUp.
+		{"-15.1", 0, "-15", nil},
+		{"-15.5", 0, "-15", nil},
+		{"-15.9", 0, "-15", nil},
+		{"15.1", 1, "15.1", nil},
+		{"-15.1", 1, "-15.1", nil},
+		{"15.17", 1, "15.2", nil},
+		{"-15.17", 1, "-15.1", nil},
+		{"15.4", -1, "20", nil},
+		{"-15.4", -1, "-10", nil},
+		{"5.4", -1, "10", nil},
+		{"-5.4", -1, "0", nil},
+		{".999", 0, "1", nil},
+		{"999999999", -9, "1000000000", nil},
+	}
+	for _, ca := range tests {
+		var dec Decimal
+		dec.FromBytes([]byte(ca.input))
+		var rounded Decimal
+		err := dec.Round(&rounded, ca.scale, ModeCeiling)
+		assert.Equal(t, err, ca.err)
+		result := rounded.ToBytes()
+		assert.Equal(t, string(result), ca.output)
+	}
+}
+
+func TestRoundWithUp(t *testing.T) {
+	tests := []struct {
+		input  string
+		scale  int
+		output string
+		err    error
+	}{
+		{"123456789.987654321", 1, "123456790.0", nil},
+		{"15.1", 0, "16", nil},
+		{"15.5", 0, "16", nil},
+		{"15.9", 0, "16", nil},
 		{"-15.1", 0, "-16", nil},
 		{"-15.5", 0, "-16", nil},
 		{"-15.9", 0, "-16", nil},
@@ -465,13 +657,66 @@ func TestRoundWithCeil(t *testing.T) {
 		var dec Decimal
 		dec.FromBytes([]byte(ca.input))
 		var rounded Decimal
-		err := dec.Round(&rounded, ca.scale, modeCeiling)
+		err := dec.Round(&rounded, ca.scale, ModeUp)
 		assert.Equal(t, err, ca.err)
 		result := rounded.ToBytes()
 		assert.Equal(t, string(result), ca.output)
 	}
 }
 
+func TestRoundWithModes(t *testing.T) {
+	modes := []RoundMode{ModeUp, ModeDown, ModeCeiling, ModeFloor, ModeHalfUp, ModeHalfDown, ModeHalfToEven, ModeUnnecessary}
+	// the table of java.math.RoundingMode, "" means ErrRoundingNecessary.
+	tests := []struct {
+		input  string
+		scale  int
+		output []string
+	}{
+		{"5.5", 0, []string{"6", "5", "6", "5", "6", "5", "6", ""}},
+		{"2.5", 0, []string{"3", "2", "3", "2", "3", "2", "2", ""}},
+		{"1.6", 0, []string{"2", "1", "2", "1", "2", "2", "2", ""}},
+		{"1.1", 0, []string{"2", "1", "2", "1", "1", "1", "1", ""}},
+		{"1.0", 0, []string{"1", "1", "1", "1", "1", "1", "1", "1"}},
+		{"-1.0", 0, []string{"-1", "-1", "-1", "-1", "-1", "-1", "-1", "-1"}},
+		{"-1.1", 0, []string{"-2", "-1", "-1", "-2", "-1", "-1", "-1", ""}},
+		{"-1.6", 0, []string{"-2", "-1", "-1", "-2", "-2", "-2", "-2", ""}},
+		{"-2.5", 0, []string{"-3", "-2", "-2", "-3", "-3", "-2", "-2", ""}},
+		{"-5.5", 0, []string{"-6", "-5", "-5", "-6", "-6", "-5", "-6", ""}},
+		{"0.1234567885", 9, []string{"0.123456789", "0.123456788", "0.123456789", "0.123456788",
+			"0.123456789", "0.123456788", "0.123456788", ""}},
+		{"0.1234567885000000001", 9, []string{"0.123456789", "0.123456788", "0.123456789", "0.123456788",
+			"0.123456789", "0.123456789", "0.123456789", ""}},
+		{"-123456789.25", 1, []string{"-123456789.3", "-123456789.2", "-123456789.2", "-123456789.3",
+			"-123456789.3", "-123456789.2", "-123456789.2", ""}},
+		{"1234567895", -1, []string{"1234567900", "1234567890", "1234567900", "1234567890",
+			"1234567900", "1234567890", "1234567900", ""}},
+		{"1234567850", -2, []string{"1234567900", "1234567800", "1234567900", "1234567800",
+			"1234567900", "1234567800", "1234567800", ""}},
+		{"1234567800", -2, []string{"1234567800", "1234567800", "1234567800", "1234567800",
+			"1234567800", "1234567800", "1234567800", "1234567800"}},
+		{"0.001", -2, []string{"100", "0", "100", "0", "0", "0", "0", ""}},
+		{"-0.001", -2, []string{"-100", "0", "0", "-100", "0", "0", "0", ""}},
+	}
+	for _, ca := range tests {
+		var dec Decimal
+		assert.Nil(t, dec.FromString(ca.input))
+		for i, mode := range modes {
+			var rounded Decimal
+			err := dec.Round(&rounded, ca.scale, mode)
+			if ca.output[i] == "" {
+				assert.Equal(t, ErrRoundingNecessary, err, "%s mode %d", ca.input, mode)
+				continue
+			}
+			assert.Nil(t, err, "%s mode %d", ca.input, mode)
+			assert.Equal(t, ca.output[i], string(rounded.ToBytes()), "%s mode %d", ca.input, mode)
+		}
+		// ModeHalfEven keeps rounding like TiDB, which is ModeHalfUp.
+		var rounded Decimal
+		assert.Nil(t, dec.Round(&rounded, ca.scale, ModeHalfEven))
+		assert.Equal(t, ca.output[4], string(rounded.ToBytes()), ca.input)
+	}
+}
+
 func TestFromString(t *testing.T) {
 	type tcase struct {
 		input  string
@@ -510,6 +755,79 @@ func TestFromString(t *testing.T) {
 		assert.Equal(t, result, ca.output)
 	}
 	wordBufLen = maxWordBufLen
+
+	// the leading zeros take no word, so all the 81 digits after the point parse back.
+	for _, str := range []string{
+		"0." + strings.Repeat("0", 72) + "123456789",
+		"-0." + strings.Repeat("1", 81),
+	} {
+		var dec Decimal
+		assert.Nil(t, dec.FromString(str), str)
+		assert.Equal(t, str, dec.String())
+	}
+	var dec Decimal
+	assert.Nil(t, dec.FromString("000123.50"))
+	assert.Equal(t, "123.50", dec.String())
+}
+
+func TestFromStringScientific(t *testing.T) {
+	tests := []struct {
+		input  string
+		output string
+	}{
+		{"1.23E+10", "12300000000"},
+		{"1E-7", "0.0000001"},
+		{"+1e-7", "0.0000001"},
+		{"-.5e3", "-500"},
+		{"1.e2", "100"},
+		{"-1.23e+010", "-12300000000"},
+		{"1.2345E-7", "0.00000012345"},
+		{"0E-10", "0.0000000000"},
+		{"0.00E-2", "0.0000"},
+		{"0E+3", "0"},
+	}
+	for _, ca := range tests {
+		var dec Decimal
+		assert.Nil(t, dec.FromString(ca.input))
+		assert.Equal(t, ca.output, string(dec.ToBytes()), ca.input)
+	}
+}
+
+func TestToJavaString(t *testing.T) {
+	tests := []struct {
+		input       string
+		sci         string
+		engineering string
+	}{
+		{"123.45", "123.45", "123.45"},
+		{"-123.4500", "-123.4500", "-123.4500"},
+		{"0.000001", "0.000001", "0.000001"},
+		{"1E-7", "1E-7", "100E-9"},
+		{"-1E-7", "-1E-7", "-100E-9"},
+		{"0.00000012345", "1.2345E-7", "123.45E-9"},
+		{"0.0000000100", "1.00E-8", "10.0E-9"},
+		{"0.000000000123", "1.23E-10", "123E-12"},
+		{"0.0000001234567891234567891", "1.234567891234567891E-7", "123.4567891234567891E-9"},
+		{"0E-8", "0E-8", "0.00E-6"},
+		{"0E-9", "0E-9", "0E-9"},
+		{"0E-10", "0E-10", "0.0E-9"},
+		{"0", "0", "0"},
+		{"1.23E+10", "12300000000", "12300000000"},
+	}
+	for _, ca := range tests {
+		var dec Decimal
+		assert.Nil(t, dec.FromString(ca.input))
+		assert.Equal(t, ca.sci, dec.ToScientificString(), ca.input)
+		assert.Equal(t, ca.engineering, dec.ToEngineeringString(), ca.input)
+		assert.Equal(t, string(dec.ToBytes()), dec.ToPlainString(), ca.input)
+
+		// the output can be parsed back to the same value.
+		var sci, engineering Decimal
+		assert.Nil(t, sci.FromString(dec.ToScientificString()))
+		assert.Nil(t, engineering.FromString(dec.ToEngineeringString()))
+		assert.Equal(t, 0, dec.Compare(&sci), ca.input)
+		assert.Equal(t, 0, dec.Compare(&engineering), ca.input)
+	}
 }
 
 func TestToBytes(t *testing.T) {
@@ -582,6 +900,34 @@ func TestToBinFromBin(t *testing.T) {
 		_, _ = dec.ToBin(tt.prec, tt.frac)
 		//assert.Equal(t,ErrBadNumber.Equal(err), IsTrue)
 	}
+
+	// the input is shorter than the layout, or a digit group is out of its range.
+	badBins := []struct {
+		bin  []byte
+		prec int
+		frac int
+	}{
+		{[]byte{0x80}, 32, 6},
+		{[]byte{0x20}, 1, 1},
+		{[]byte{0x8a}, 1, 0},
+	}
+	for _, tt := range badBins {
+		_, err := dec.FromBin(tt.bin, tt.prec, tt.frac)
+		assert.Equal(t, ErrBadNumber, err)
+		assert.True(t, dec.IsZero())
+	}
+
+	// the largest groups of each size are still accepted.
+	for _, str := range []string{"9", "-9.9", "99999.99999", "-9999999999.999999999"} {
+		from := NewDecFromStringForTest(str)
+		prec, frac := from.PrecisionAndFrac()
+		bin, err := from.ToBin(prec, frac)
+		assert.Nil(t, err, str)
+		var to Decimal
+		_, err = to.FromBin(bin, prec, frac)
+		assert.Nil(t, err, str)
+		assert.Equal(t, str, to.String())
+	}
 }
 
 func TestCompare(t *testing.T) {
@@ -745,10 +1091,13 @@ func TestMul(t *testing.T) {
 		{"123456", "9876543210", "1219318518533760", nil},
 		{"123", "0.01", "1.23", nil},
 		{"123", "0", "0", nil},
-		{"-0.0000000000000000000000000000000000000000000000000017382578996420603", "-13890436710184412000000000000000000000000000000000000000000000000000000000000", "0.000000000000000000000000000000", ErrTruncated},
+		// MySQL gives 0.000000000000000000000000000000 as it drops the low words of the factors.
+		{"-0.0000000000000000000000000000000000000000000000000017382578996420603", "-13890436710184412000000000000000000000000000000000000000000000000000000000000", "24145161340956125866942404.000000000000000000000000000000", ErrTruncated},
 		{"1" + strings.Repeat("0", 60), "1" + strings.Repeat("0", 60), "0", ErrOverflow},
 		{"0.5999991229316", "0.918755041726043", "0.5512522192246113614062276588", nil},
 		{"0.5999991229317", "0.918755041726042", "0.5512522192247026369112773314", nil},
+		// the truncated product keeps the carries of the dropped digits.
+		{strings.Repeat("9", 45) + "." + strings.Repeat("9", 30), "0." + strings.Repeat("9", 30), "999999999999999999999999999998999999999999999.999999999999999999999999999999", ErrTruncated},
 	}
 	for _, tt := range tests {
 		var a, b, product Decimal
@@ -858,6 +1207,187 @@ func TestDivMod(t *testing.T) {
 	}
 }
 
+func TestDecimalDivMod(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		mode     DivisionMode
+		quo, rem string
+		err      error
+	}{
+		{"7", "2", DivisionTruncated, "3", "1", nil},
+		{"-7", "2", DivisionTruncated, "-3", "-1", nil},
+		{"7", "-2", DivisionTruncated, "-3", "1", nil},
+		{"-7", "-2", DivisionTruncated, "3", "-1", nil},
+		{"7", "2", DivisionFloored, "3", "1", nil},
+		{"-7", "2", DivisionFloored, "-4", "1", nil},
+		{"7", "-2", DivisionFloored, "-4", "-1", nil},
+		{"-7", "-2", DivisionFloored, "3", "-1", nil},
+		{"7", "2", DivisionEuclidean, "3", "1", nil},
+		{"-7", "2", DivisionEuclidean, "-4", "1", nil},
+		{"7", "-2", DivisionEuclidean, "-3", "1", nil},
+		{"-7", "-2", DivisionEuclidean, "4", "1", nil},
+		{"-6", "2", DivisionFloored, "-3", "0", nil},
+		{"7.50", "2", DivisionTruncated, "3.00", "1.50", nil},
+		{"-7.5", "0.25", DivisionEuclidean, "-30", "0.00", nil},
+		{"-7.6", "0.25", DivisionEuclidean, "-31", "0.15", nil},
+		{"234.567", "10.555", DivisionTruncated, "22", "2.357", nil},
+		{"0.0000000001", "1.0", DivisionFloored, "0.000000000", "0.0000000001", nil},
+		{"-0.0000000001", "1.0", DivisionFloored, "-1.000000000", "0.9999999999", nil},
+		{"99999999999999999999999999999999999999", "3", DivisionTruncated, "33333333333333333333333333333333333333", "0", nil},
+		{"0", "3.5", DivisionEuclidean, "0", "0.0", nil},
+		{"1", "0", DivisionTruncated, "", "", ErrDivByZero},
+		{"1" + strings.Repeat("0", 70), "0.000000000000000000000000000001", DivisionTruncated,
+			strings.Repeat("9", 81), "0.000000000000000000000000000000", ErrOverflow},
+	}
+	for _, tt := range tests {
+		var a, b, quo, rem Decimal
+		assert.NoError(t, a.FromString(tt.a))
+		assert.NoError(t, b.FromString(tt.b))
+		err := DecimalDivModWithMode(&a, &b, &quo, &rem, tt.mode)
+		assert.Equal(t, tt.err, err, "%s / %s", tt.a, tt.b)
+		if err == ErrDivByZero {
+			continue
+		}
+		assert.Equal(t, tt.quo, quo.String(), "%s / %s", tt.a, tt.b)
+		assert.Equal(t, tt.rem, rem.String(), "%s %% %s", tt.a, tt.b)
+	}
+
+	// the same as DecimalMod.
+	var a, b, quo, rem, mod Decimal
+	assert.NoError(t, a.FromString("51"))
+	assert.NoError(t, b.FromString("0.003430"))
+	assert.NoError(t, DecimalDivMod(&a, &b, &quo, &rem))
+	assert.NoError(t, DecimalMod(&a, &b, &mod))
+	assert.Equal(t, "14868", quo.String())
+	assert.Equal(t, mod.String(), rem.String())
+}
+
+func TestHessianValue(t *testing.T) {
+	var a, b, to Decimal
+	assert.Equal(t, "0", a.HessianValue())
+	assert.Nil(t, a.FromString("123.45"))
+	assert.Equal(t, "123.45", a.JavaValue)
+	assert.Nil(t, b.FromString("-0.5"))
+	assert.Equal(t, "-0.5", b.JavaValue)
+
+	// roundTrip encodes Value like hessian, which reads the field by reflection, and decodes it back.
+	roundTrip := func(op string, d *Decimal) {
+		var back Decimal
+		back.JavaValue = d.JavaValue
+		assert.Nil(t, back.SetHessianValue(back.JavaValue), op)
+		assert.Equal(t, 0, back.Compare(d), op)
+		assert.Equal(t, string(d.ToBytes()), string(back.ToBytes()), op)
+		assert.Equal(t, d.JavaValue, back.JavaValue, op)
+	}
+	assert.Nil(t, DecimalAdd(&a, &b, &to))
+	assert.Equal(t, "122.95", to.JavaValue)
+	roundTrip("add", &to)
+	assert.Nil(t, DecimalSub(&a, &b, &to))
+	assert.Equal(t, "123.95", to.JavaValue)
+	roundTrip("sub", &to)
+	assert.Nil(t, DecimalMul(&a, &b, &to))
+	assert.Equal(t, "-61.725", to.JavaValue)
+	roundTrip("mul", &to)
+	assert.Nil(t, DecimalDiv(&a, &b, &to, DivFracIncr))
+	assert.Equal(t, "-246.900000000000000000", to.JavaValue)
+	roundTrip("div", &to)
+	assert.Nil(t, DecimalMod(&a, &b, &to))
+	assert.Equal(t, "0.45", to.JavaValue)
+	roundTrip("mod", &to)
+	assert.Nil(t, a.Round(&to, 1, ModeHalfUp))
+	assert.Equal(t, "123.5", to.JavaValue)
+	roundTrip("round", &to)
+	assert.Nil(t, to.Shift(-2))
+	assert.Equal(t, "1.235", to.JavaValue)
+	roundTrip("shift", &to)
+	assert.Equal(t, "-123.45", DecimalNeg(&a).JavaValue)
+	assert.Equal(t, "-42", NewDecFromInt(-42).JavaValue)
+
+	// the operations writing to their operand keep it consistent as well.
+	assert.Nil(t, a.Round(&a, 0, ModeHalfUp))
+	assert.Equal(t, "123", a.JavaValue)
+	roundTrip("round in place", &a)
+	assert.Nil(t, a.FromString("123.45"))
+
+	bin, err := a.ToBin(10, 3)
+	assert.Nil(t, err)
+	_, err = to.FromBin(bin, 10, 3)
+	assert.Nil(t, err)
+	assert.Equal(t, "123.450", to.JavaValue)
+
+	// java writes BigDecimal.toString(), which may be in scientific notation.
+	tests := []struct {
+		value  string
+		output string
+	}{
+		{"1.23E+5", "123000"},
+		{"1E-7", "0.0000001"},
+		{"-0.00100", "-0.00100"},
+		{"12345678901234567890.123456789", "12345678901234567890.123456789"},
+	}
+	for _, ca := range tests {
+		var dec Decimal
+		assert.Nil(t, dec.SetHessianValue(ca.value))
+		assert.Equal(t, ca.output, string(dec.ToBytes()))
+		assert.Equal(t, ca.output, dec.HessianValue())
+	}
+	assert.Equal(t, ErrBadNumber, to.SetHessianValue(""))
+}
+
+func TestUnscaledBigInt(t *testing.T) {
+	tests := []struct {
+		unscaled string
+		scale    int32
+		output   string
+		err      error
+	}{
+		{"12345", 2, "123.45", nil},
+		{"-12345", 2, "-123.45", nil},
+		{"12345", 0, "12345", nil},
+		{"12345", 7, "0.0012345", nil},
+		{"-12345", 5, "-0.12345", nil},
+		{"123", -2, "12300", nil},
+		{"-1", -10, "-10000000000", nil},
+		{"0", 3, "0.000", nil},
+		{"0", -3, "0", nil},
+		{"0", -1000, "0", nil},
+		{"123456789012345678901234567890", 10, "12345678901234567890.1234567890", nil},
+		{"1", -80, "1" + strings.Repeat("0", 80), nil},
+		{"1", -81, strings.Repeat("9", 81), ErrOverflow},
+		{"-1", -1000, "-" + strings.Repeat("9", 81), ErrOverflow},
+		{"1", 1000, "0", ErrTruncated},
+	}
+	for _, ca := range tests {
+		unscaled, ok := new(big.Int).SetString(ca.unscaled, 10)
+		assert.True(t, ok)
+		dec, err := NewDecFromUnscaledBigInt(unscaled, ca.scale)
+		assert.Equal(t, ca.err, err, ca.unscaled)
+		assert.Equal(t, ca.output, string(dec.ToBytes()), ca.unscaled)
+		if err != nil {
+			continue
+		}
+		result, scale := dec.ToUnscaledBigInt()
+		if ca.scale >= 0 {
+			assert.Equal(t, ca.unscaled, result.String())
+			assert.Equal(t, ca.scale, scale)
+		} else {
+			assert.Equal(t, int32(0), scale)
+			assert.Equal(t, ca.output, result.String())
+		}
+	}
+
+	var dec Decimal
+	assert.Nil(t, dec.FromString("-1.2300"))
+	unscaled, scale := dec.ToUnscaledBigInt()
+	assert.Equal(t, "-12300", unscaled.String())
+	assert.Equal(t, int32(4), scale)
+	var dec2 Decimal
+	assert.Nil(t, dec2.FromString("1e-3"))
+	unscaled, scale = dec2.ToUnscaledBigInt()
+	assert.Equal(t, "1", unscaled.String())
+	assert.Equal(t, int32(3), scale)
+}
+
 func TestMaxOrMin(t *testing.T) {
 	type tcase struct {
 		neg    bool
@@ -19,6 +19,7 @@ package gxbig
 
 import (
 	"math"
+	"math/big"
 	"strings"
 	"unicode"
 )
@@ -96,6 +97,17 @@ func TruncateFloat(f float64, flen int, decimal int) (float64, error) {
 	return f, err
 }
 
+var (
+	bigOne      = big.NewInt(1)
+	bigTen      = big.NewInt(10)
+	bigWordBase = big.NewInt(wordBase)
+)
+
+// bigPow10 returns 10^n, n must not be negative.
+func bigPow10(n int) *big.Int {
+	return new(big.Int).Exp(bigTen, big.NewInt(int64(n)), nil)
+}
+
 func isSpace(c byte) bool {
 	return c == ' ' || c == '\t'
 }
@@ -0,0 +1,292 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxbig
+
+import (
+	"math/big"
+	"strings"
+)
+
+import (
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrUnknownCurrency  = errors.Errorf("Unknown Currency")
+	ErrCurrencyMismatch = errors.Errorf("Currency Mismatch")
+)
+
+// currencyScales maps the ISO-4217 currency codes to their minor units.
+var currencyScales = map[string]int{}
+
+func init() {
+	for scale, codes := range []string{
+		0: "BIF CLP DJF GNF ISK JPY KMF KRW PYG RWF UGX UYI VND VUV XAF XOF XPF",
+		2: "AED AFN ALL AMD ANG AOA ARS AUD AWG AZN BAM BBD BDT BGN BMD BND BOB BOV BRL BSD BTN BWP BYN BZD " +
+			"CAD CDF CHE CHF CHW CNY COP COU CRC CUC CUP CVE CZK DKK DOP DZD EGP ERN ETB EUR FJD FKP GBP GEL " +
+			"GHS GIP GMD GTQ GYD HKD HNL HTG HUF IDR ILS INR IRR JMD KES KGS KHR KPW KYD KZT LAK LBP LKR LRD " +
+			"LSL MAD MDL MGA MKD MMK MNT MOP MRU MUR MVR MWK MXN MXV MYR MZN NAD NGN NIO NOK NPR NZD PAB PEN " +
+			"PGK PHP PKR PLN QAR RON RSD RUB SAR SBD SCR SDG SEK SGD SHP SLE SLL SOS SRD SSP STN SVC SYP SZL " +
+			"THB TJS TMT TOP TRY TTD TWD TZS UAH USD USN UYU UZS VED VES WST XCD YER ZAR ZMW ZWL",
+		3: "BHD IQD JOD KWD LYD OMR TND",
+		4: "CLF UYW",
+	} {
+		for _, code := range strings.Fields(codes) {
+			currencyScales[code] = scale
+		}
+	}
+}
+
+// CurrencyScale returns the minor units of the ISO-4217 currency code, e.g. 2 of USD and 0 of JPY.
+func CurrencyScale(currency string) (int, error) {
+	scale, ok := currencyScales[strings.ToUpper(currency)]
+	if !ok {
+		return 0, ErrUnknownCurrency
+	}
+	return scale, nil
+}
+
+// Money is an amount of an ISO-4217 currency, the amount always has the minor units of the currency
+// as its scale, e.g. USD 1.50 and JPY 150. Money is immutable, and the arithmetic of different
+// currencies returns ErrCurrencyMismatch.
+type Money struct {
+	amount   Decimal
+	currency string
+}
+
+// NewMoney returns the money of amount in currency, the amount is rounded to the scale of
+// the currency with roundMode, pass ModeUnnecessary to reject the amounts with more digits.
+func NewMoney(amount *Decimal, currency string, roundMode RoundMode) (*Money, error) {
+	scale, err := CurrencyScale(currency)
+	if err != nil {
+		return nil, err
+	}
+	m := &Money{currency: strings.ToUpper(currency)}
+	if err = amount.Round(&m.amount, scale, roundMode); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// NewMoneyFromMinorUnits returns the money of units in the minor unit of currency, e.g. 150 cents of USD is USD 1.50.
+func NewMoneyFromMinorUnits(units int64, currency string) (*Money, error) {
+	scale, err := CurrencyScale(currency)
+	if err != nil {
+		return nil, err
+	}
+	return newMoneyFromUnits(big.NewInt(units), strings.ToUpper(currency), scale)
+}
+
+func newMoneyFromUnits(units *big.Int, currency string, scale int) (*Money, error) {
+	m := &Money{currency: currency}
+	if err := m.amount.FromUnscaledBigInt(units, int32(scale)); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ParseMoney parses the money formatted by String like "USD 12.34", the reversed order "12.34 USD"
+// is accepted as well. The amount must not have more digits after the point than the currency.
+func ParseMoney(str string) (*Money, error) {
+	fields := strings.Fields(str)
+	if len(fields) != 2 {
+		return nil, ErrBadNumber
+	}
+	currency, amount := fields[0], fields[1]
+	if _, err := CurrencyScale(amount); err == nil {
+		currency, amount = amount, currency
+	}
+	dec := new(Decimal)
+	if err := dec.FromString(amount); err != nil {
+		return nil, err
+	}
+	return NewMoney(dec, currency, ModeUnnecessary)
+}
+
+// Amount returns the amount of the money.
+func (m *Money) Amount() *Decimal {
+	return m.amount.clone()
+}
+
+// Currency returns the upper case ISO-4217 currency code of the money.
+func (m *Money) Currency() string {
+	return m.currency
+}
+
+// MinorUnits returns the amount in the minor unit of the currency, e.g. 150 of USD 1.50.
+// It returns ErrOverflow if the units do not fit into int64.
+func (m *Money) MinorUnits() (int64, error) {
+	units, _ := m.amount.ToUnscaledBigInt()
+	if !units.IsInt64() {
+		return 0, ErrOverflow
+	}
+	return units.Int64(), nil
+}
+
+// String returns the money like "USD 12.34".
+func (m *Money) String() string {
+	return m.currency + " " + string(m.amount.ToBytes())
+}
+
+// IsZero checks whether the amount is zero.
+func (m *Money) IsZero() bool {
+	return m.amount.IsZero()
+}
+
+// IsNegative checks whether the amount is less than zero.
+func (m *Money) IsNegative() bool {
+	return m.amount.IsNegative() && !m.amount.IsZero()
+}
+
+// Compare compares the amounts of the same currency, returns -1/0/1.
+func (m *Money) Compare(o *Money) (int, error) {
+	if m.currency != o.currency {
+		return 0, ErrCurrencyMismatch
+	}
+	return m.amount.Compare(&o.amount), nil
+}
+
+// Equal checks whether the currencies and the amounts are both equal.
+func (m *Money) Equal(o *Money) bool {
+	cmp, err := m.Compare(o)
+	return err == nil && cmp == 0
+}
+
+// Add returns m + o.
+func (m *Money) Add(o *Money) (*Money, error) {
+	if m.currency != o.currency {
+		return nil, ErrCurrencyMismatch
+	}
+	to := &Money{currency: m.currency}
+	if err := DecimalAdd(&m.amount, &o.amount, &to.amount); err != nil {
+		return nil, err
+	}
+	return to, nil
+}
+
+// Sub returns m - o.
+func (m *Money) Sub(o *Money) (*Money, error) {
+	if m.currency != o.currency {
+		return nil, ErrCurrencyMismatch
+	}
+	to := &Money{currency: m.currency}
+	if err := DecimalSub(&m.amount, &o.amount, &to.amount); err != nil {
+		return nil, err
+	}
+	return to, nil
+}
+
+// Neg returns -m.
+func (m *Money) Neg() *Money {
+	return &Money{amount: *m.amount.Neg(), currency: m.currency}
+}
+
+// Abs returns |m|.
+func (m *Money) Abs() *Money {
+	return &Money{amount: *m.amount.Abs(), currency: m.currency}
+}
+
+// Mul returns m * factor, the amount is rounded to the scale of the currency with roundMode.
+func (m *Money) Mul(factor *Decimal, roundMode RoundMode) (*Money, error) {
+	product, err := m.amount.Mul(factor)
+	if err != nil {
+		return nil, err
+	}
+	return NewMoney(product, m.currency, roundMode)
+}
+
+// Allocate splits the money into n parts as even as possible without losing a minor unit,
+// the leading parts take the remainder, e.g. USD 100.00 is split into 33.34, 33.33 and 33.33.
+func (m *Money) Allocate(n int) ([]*Money, error) {
+	if n <= 0 {
+		return nil, ErrBadNumber
+	}
+	weights := make([]*big.Int, n)
+	for i := range weights {
+		weights[i] = bigOne
+	}
+	return m.allocate(weights)
+}
+
+// AllocateByRatios splits the money in proportion to ratios without losing a minor unit, the remainder
+// is taken by the leading parts of non-zero ratios one unit each, e.g. USD 0.05 by 3:7 is 0.02 and 0.03.
+// The ratios must not be negative and their sum must be positive.
+func (m *Money) AllocateByRatios(ratios []*Decimal) ([]*Money, error) {
+	if len(ratios) == 0 {
+		return nil, ErrBadNumber
+	}
+	// align the ratios to integers of the same scale.
+	weights := make([]*big.Int, len(ratios))
+	maxScale := int32(0)
+	for i, ratio := range ratios {
+		if ratio.IsNegative() && !ratio.IsZero() {
+			return nil, ErrBadNumber
+		}
+		var scale int32
+		weights[i], scale = ratio.ToUnscaledBigInt()
+		if scale > maxScale {
+			maxScale = scale
+		}
+	}
+	for i, ratio := range ratios {
+		_, scale := ratio.ToUnscaledBigInt()
+		weights[i].Mul(weights[i], bigPow10(int(maxScale-scale)))
+	}
+	return m.allocate(weights)
+}
+
+func (m *Money) allocate(weights []*big.Int) ([]*Money, error) {
+	total := new(big.Int)
+	for _, w := range weights {
+		total.Add(total, w)
+	}
+	if total.Sign() == 0 {
+		return nil, ErrBadNumber
+	}
+
+	units, scale := m.amount.ToUnscaledBigInt()
+	negative := units.Sign() < 0
+	units.Abs(units)
+	shares := make([]*big.Int, len(weights))
+	remainder := new(big.Int).Set(units)
+	for i, w := range weights {
+		shares[i] = new(big.Int).Mul(units, w)
+		shares[i].Quo(shares[i], total)
+		remainder.Sub(remainder, shares[i])
+	}
+	// the remainder is less than the number of the non-zero weights.
+	for i := 0; remainder.Sign() > 0; i++ {
+		if weights[i].Sign() != 0 {
+			shares[i].Add(shares[i], bigOne)
+			remainder.Sub(remainder, bigOne)
+		}
+	}
+
+	parts := make([]*Money, len(shares))
+	for i, share := range shares {
+		if negative {
+			share.Neg(share)
+		}
+		part, err := newMoneyFromUnits(share, m.currency, int(scale))
+		if err != nil {
+			return nil, err
+		}
+		parts[i] = part
+	}
+	return parts, nil
+}
@@ -0,0 +1,169 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxbig
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func newMoneyForTest(str string) *Money {
+	m, err := ParseMoney(str)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+func moneyStrings(parts []*Money) []string {
+	strs := make([]string, 0, len(parts))
+	for _, p := range parts {
+		strs = append(strs, p.String())
+	}
+	return strs
+}
+
+func TestNewMoney(t *testing.T) {
+	tests := []struct {
+		amount   string
+		currency string
+		mode     RoundMode
+		output   string
+		err      error
+	}{
+		{"1.5", "USD", ModeUnnecessary, "USD 1.50", nil},
+		{"1.005", "usd", ModeHalfUp, "USD 1.01", nil},
+		{"1.005", "USD", ModeHalfToEven, "USD 1.00", nil},
+		{"150.5", "JPY", ModeDown, "JPY 150", nil},
+		{"-0.0005", "KWD", ModeFloor, "KWD -0.001", nil},
+		{"1.005", "USD", ModeUnnecessary, "", ErrRoundingNecessary},
+		{"1", "XYZ", ModeHalfUp, "", ErrUnknownCurrency},
+	}
+	for _, ca := range tests {
+		m, err := NewMoney(NewDecFromStringForTest(ca.amount), ca.currency, ca.mode)
+		assert.Equal(t, ca.err, err, ca.amount)
+		if err == nil {
+			assert.Equal(t, ca.output, m.String(), ca.amount)
+		}
+	}
+
+	m, err := NewMoneyFromMinorUnits(-150, "EUR")
+	assert.Nil(t, err)
+	assert.Equal(t, "EUR -1.50", m.String())
+	units, err := m.MinorUnits()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(-150), units)
+	assert.Equal(t, "-1.50", string(m.Amount().ToBytes()))
+	assert.Equal(t, "EUR", m.Currency())
+
+	scale, err := CurrencyScale("bhd")
+	assert.Nil(t, err)
+	assert.Equal(t, 3, scale)
+}
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		input  string
+		output string
+		err    error
+	}{
+		{"USD 12.34", "USD 12.34", nil},
+		{" 12.3  eur ", "EUR 12.30", nil},
+		{"JPY -500", "JPY -500", nil},
+		{"USD 12.345", "", ErrRoundingNecessary},
+		{"USD", "", ErrBadNumber},
+		{"USD abc", "", ErrBadNumber},
+		{"ABC 12", "", ErrUnknownCurrency},
+	}
+	for _, ca := range tests {
+		m, err := ParseMoney(ca.input)
+		assert.Equal(t, ca.err, err, ca.input)
+		if err == nil {
+			assert.Equal(t, ca.output, m.String(), ca.input)
+		}
+	}
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	a, b := newMoneyForTest("USD 10.25"), newMoneyForTest("USD -0.30")
+	sum, err := a.Add(b)
+	assert.Nil(t, err)
+	assert.Equal(t, "USD 9.95", sum.String())
+	diff, err := a.Sub(b)
+	assert.Nil(t, err)
+	assert.Equal(t, "USD 10.55", diff.String())
+	assert.Equal(t, "USD 0.30", b.Neg().String())
+	assert.Equal(t, "USD 0.30", b.Abs().String())
+	assert.True(t, b.IsNegative())
+	assert.False(t, b.Abs().IsZero())
+
+	product, err := a.Mul(NewDecFromStringForTest("0.075"), ModeHalfToEven)
+	assert.Nil(t, err)
+	assert.Equal(t, "USD 0.77", product.String())
+
+	cmp, err := a.Compare(b)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, cmp)
+	assert.True(t, a.Equal(newMoneyForTest("10.250 USD")))
+
+	eur := newMoneyForTest("EUR 10.25")
+	assert.False(t, a.Equal(eur))
+	_, err = a.Add(eur)
+	assert.Equal(t, ErrCurrencyMismatch, err)
+	_, err = a.Sub(eur)
+	assert.Equal(t, ErrCurrencyMismatch, err)
+	_, err = a.Compare(eur)
+	assert.Equal(t, ErrCurrencyMismatch, err)
+}
+
+func TestMoneyAllocate(t *testing.T) {
+	parts, err := newMoneyForTest("USD 100").Allocate(3)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"USD 33.34", "USD 33.33", "USD 33.33"}, moneyStrings(parts))
+
+	parts, err = newMoneyForTest("USD -0.05").Allocate(3)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"USD -0.02", "USD -0.02", "USD -0.01"}, moneyStrings(parts))
+
+	parts, err = newMoneyForTest("JPY 100").Allocate(3)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"JPY 34", "JPY 33", "JPY 33"}, moneyStrings(parts))
+
+	_, err = newMoneyForTest("USD 1").Allocate(0)
+	assert.Equal(t, ErrBadNumber, err)
+
+	parts, err = newMoneyForTest("USD 0.05").AllocateByRatios(newDecsForTest("3", "7"))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"USD 0.02", "USD 0.03"}, moneyStrings(parts))
+
+	parts, err = newMoneyForTest("USD 100").AllocateByRatios(newDecsForTest("0", "0.5", "0.25", "0.25"))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"USD 0.00", "USD 50.00", "USD 25.00", "USD 25.00"}, moneyStrings(parts))
+
+	parts, err = newMoneyForTest("USD 10").AllocateByRatios(newDecsForTest("0", "1", "1", "1"))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"USD 0.00", "USD 3.34", "USD 3.33", "USD 3.33"}, moneyStrings(parts))
+
+	_, err = newMoneyForTest("USD 1").AllocateByRatios(newDecsForTest("1", "-1"))
+	assert.Equal(t, ErrBadNumber, err)
+	_, err = newMoneyForTest("USD 1").AllocateByRatios(newDecsForTest("0"))
+	assert.Equal(t, ErrBadNumber, err)
+}
@@ -0,0 +1,415 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxbig
+
+import (
+	"math"
+	"math/big"
+)
+
+// UnboundedDecimal is the companion of Decimal without the 81 digits limit of wordBuf.
+// The words of its unscaled value grow dynamically, so just like java.math.BigDecimal
+// it never overflows.
+//
+// UnboundedDecimal must not be copied by value, use Set instead.
+type UnboundedDecimal struct {
+	// value is the unscaled value, the decimal equals value * 10^(-digitsFrac).
+	value big.Int
+
+	// the number of decimal digits after the point.
+	digitsFrac int
+}
+
+// maxUnboundedExponent is the max absolute value of the exponent accepted by UnboundedDecimal.FromBytes.
+const maxUnboundedExponent = 1 << 16
+
+// NewUnboundedDecFromInt creates a UnboundedDecimal from int.
+func NewUnboundedDecFromInt(i int64) *UnboundedDecimal {
+	return new(UnboundedDecimal).FromInt(i)
+}
+
+// NewUnboundedDecFromDecimal creates a UnboundedDecimal from Decimal.
+func NewUnboundedDecFromDecimal(d *Decimal) *UnboundedDecimal {
+	return new(UnboundedDecimal).FromDecimal(d)
+}
+
+// Set sets d to from and returns d.
+func (d *UnboundedDecimal) Set(from *UnboundedDecimal) *UnboundedDecimal {
+	if d != from {
+		d.value.Set(&from.value)
+		d.digitsFrac = from.digitsFrac
+	}
+	return d
+}
+
+// IsNegative returns whether a decimal is negative.
+func (d *UnboundedDecimal) IsNegative() bool {
+	return d.value.Sign() < 0
+}
+
+// IsZero checks whether it's a zero decimal.
+func (d *UnboundedDecimal) IsZero() bool {
+	return d.value.Sign() == 0
+}
+
+// GetDigitsFrac returns the digitsFrac.
+func (d *UnboundedDecimal) GetDigitsFrac() int {
+	return d.digitsFrac
+}
+
+// PrecisionAndFrac returns the internal precision and frac number.
+func (d *UnboundedDecimal) PrecisionAndFrac() (precision, frac int) {
+	frac = d.digitsFrac
+	precision = len(new(big.Int).Abs(&d.value).String())
+	if precision < frac {
+		precision = frac
+	}
+	return
+}
+
+// FromInt sets the decimal value from int64.
+func (d *UnboundedDecimal) FromInt(val int64) *UnboundedDecimal {
+	d.value.SetInt64(val)
+	d.digitsFrac = 0
+	return d
+}
+
+// FromUint sets the decimal value from uint64.
+func (d *UnboundedDecimal) FromUint(val uint64) *UnboundedDecimal {
+	d.value.SetUint64(val)
+	d.digitsFrac = 0
+	return d
+}
+
+// FromDecimal sets the decimal value from a Decimal.
+func (d *UnboundedDecimal) FromDecimal(from *Decimal) *UnboundedDecimal {
+	// The printable representation of a Decimal is always a valid number.
+	_ = d.FromBytes(from.ToBytes())
+	return d
+}
+
+// ToDecimal converts the decimal to a Decimal, returns ErrOverflow or ErrTruncated
+// if it does not fit into the word buffer of Decimal. Like FromUnscaledBigInt, the result
+// is the max decimal on overflow.
+func (d *UnboundedDecimal) ToDecimal() (*Decimal, error) {
+	dec := new(Decimal)
+	err := dec.FromBytes(d.ToBytes())
+	if err == ErrOverflow {
+		maxDecimal(wordBufLen*digitsPerWord, 0, dec)
+		dec.negative = d.IsNegative()
+		dec.updateJavaValue()
+	}
+	return dec, err
+}
+
+// ToInt returns int part of the decimal, returns the result and errcode.
+func (d *UnboundedDecimal) ToInt() (int64, error) {
+	q, r := new(big.Int).QuoRem(&d.value, bigPow10(d.digitsFrac), new(big.Int))
+	if !q.IsInt64() {
+		if q.Sign() < 0 {
+			return math.MinInt64, ErrOverflow
+		}
+		return math.MaxInt64, ErrOverflow
+	}
+	if r.Sign() != 0 {
+		return q.Int64(), ErrTruncated
+	}
+	return q.Int64(), nil
+}
+
+// String returns the decimal string representation.
+func (d *UnboundedDecimal) String() string {
+	return string(d.ToBytes())
+}
+
+// ToBytes converts decimal to its printable string representation without rounding.
+func (d *UnboundedDecimal) ToBytes() []byte {
+	digits := new(big.Int).Abs(&d.value).String()
+	str := make([]byte, 0, len(digits)+d.digitsFrac+3)
+	if d.value.Sign() < 0 {
+		str = append(str, '-')
+	}
+	digitsInt := len(digits) - d.digitsFrac
+	if digitsInt > 0 {
+		str = append(str, digits[:digitsInt]...)
+	} else {
+		str = append(str, '0')
+	}
+	if d.digitsFrac > 0 {
+		str = append(str, '.')
+		for ; digitsInt < 0; digitsInt++ {
+			str = append(str, '0')
+		}
+		str = append(str, digits[digitsInt:]...)
+	}
+	return str
+}
+
+// FromString parses decimal from string.
+func (d *UnboundedDecimal) FromString(str string) error {
+	return d.FromBytes([]byte(str))
+}
+
+// FromBytes parses decimal from bytes, the syntax is the same as Decimal.FromBytes.
+// The absolute value of the exponent is limited to maxUnboundedExponent, it is ErrOverflow otherwise.
+func (d *UnboundedDecimal) FromBytes(str []byte) error {
+	for i := 0; i < len(str); i++ {
+		if !isSpace(str[i]) {
+			str = str[i:]
+			break
+		}
+	}
+	d.value.SetInt64(0)
+	d.digitsFrac = 0
+	if len(str) == 0 {
+		return ErrBadNumber
+	}
+	negative := false
+	switch str[0] {
+	case '-':
+		negative = true
+		fallthrough
+	case '+':
+		str = str[1:]
+	}
+	var strIdx int
+	for strIdx < len(str) && isDigit(str[strIdx]) {
+		strIdx++
+	}
+	digitsInt := strIdx
+	endIdx := strIdx
+	if strIdx < len(str) && str[strIdx] == '.' {
+		endIdx++
+		for endIdx < len(str) && isDigit(str[endIdx]) {
+			endIdx++
+		}
+	}
+	digitsFrac := endIdx - digitsInt - 1
+	if digitsFrac < 0 {
+		digitsFrac = 0
+	}
+	if digitsInt+digitsFrac == 0 {
+		return ErrBadNumber
+	}
+	digits := make([]byte, 0, digitsInt+digitsFrac)
+	digits = append(digits, str[:digitsInt]...)
+	if digitsFrac > 0 {
+		digits = append(digits, str[digitsInt+1:endIdx]...)
+	}
+	d.value.SetString(string(digits), 10)
+	d.digitsFrac = digitsFrac
+	if negative {
+		d.value.Neg(&d.value)
+	}
+
+	var err error
+	if endIdx+1 <= len(str) && (str[endIdx] == 'e' || str[endIdx] == 'E') {
+		exponent, err1 := strToInt(string(str[endIdx+1:]))
+		if err1 != nil {
+			err = err1
+			if err != ErrTruncated {
+				d.value.SetInt64(0)
+				d.digitsFrac = 0
+				return err
+			}
+		}
+		// the shift materializes the exponent as digits, so a huge one like 1E1000000000 overflows
+		// instead of exhausting the memory.
+		if exponent > maxUnboundedExponent || exponent < -maxUnboundedExponent {
+			d.value.SetInt64(0)
+			d.digitsFrac = 0
+			return ErrOverflow
+		}
+		_ = d.Shift(int(exponent))
+	}
+	return err
+}
+
+// Shift shifts decimal digits in given number, shift > 0 means shift to left shift,
+// shift < 0 means right shift. In fact it is multiplying on 10^shift.
+// The shift never loses digits, but like the exponent of FromBytes the absolute value of shift
+// is limited to maxUnboundedExponent, it returns ErrOverflow and leaves d unchanged otherwise.
+func (d *UnboundedDecimal) Shift(shift int) error {
+	if shift > maxUnboundedExponent || shift < -maxUnboundedExponent {
+		return ErrOverflow
+	}
+	d.digitsFrac -= shift
+	if d.digitsFrac < 0 {
+		d.value.Mul(&d.value, bigPow10(-d.digitsFrac))
+		d.digitsFrac = 0
+	}
+	return nil
+}
+
+// Round rounds the decimal to "frac" digits, see Decimal.Round.
+//
+//	to			- result buffer. d == to is allowed
+//	frac			- to what position after fraction point to round. can be negative!
+//	roundMode		- the round mode
+//
+// RETURN VALUE
+//
+//	nil, or ErrRoundingNecessary if roundMode is ModeUnnecessary and
+//	some non-zero digits would be discarded, or ErrOverflow if frac is more than
+//	maxUnboundedExponent digits away from the fraction digits of d, "to" is untouched then.
+func (d *UnboundedDecimal) Round(to *UnboundedDecimal, frac int, roundMode RoundMode) error {
+	if frac-d.digitsFrac > maxUnboundedExponent || d.digitsFrac-frac > maxUnboundedExponent {
+		return ErrOverflow
+	}
+	if frac >= d.digitsFrac {
+		to.value.Mul(&d.value, bigPow10(frac-d.digitsFrac))
+		to.digitsFrac = frac
+		return nil
+	}
+
+	// discarded is the number of discarded digits.
+	discarded := d.digitsFrac - frac
+	q, r := new(big.Int).QuoRem(&d.value, bigPow10(discarded), new(big.Int))
+	negative := d.value.Sign() < 0
+	if r.Sign() != 0 {
+		if roundMode == ModeUnnecessary {
+			return ErrRoundingNecessary
+		}
+		// firstDigit is the first discarded digit and sticky holds the others.
+		first, sticky := new(big.Int).QuoRem(r.Abs(r), bigPow10(discarded-1), new(big.Int))
+		lastDigit := new(big.Int).Rem(new(big.Int).Abs(q), bigTen)
+		if roundMode.needIncrement(negative, int32(lastDigit.Int64()), int32(first.Int64()), sticky.Sign() != 0) {
+			if negative {
+				q.Sub(q, bigOne)
+			} else {
+				q.Add(q, bigOne)
+			}
+		}
+	}
+	to.value.Set(q)
+	to.digitsFrac = frac
+	if frac < 0 {
+		_ = to.Shift(0)
+	}
+	return nil
+}
+
+// Compare compares one decimal to another, returns -1/0/1.
+func (d *UnboundedDecimal) Compare(to *UnboundedDecimal) int {
+	v1, v2, _ := alignUnboundedDecimals(d, to)
+	return v1.Cmp(v2)
+}
+
+// alignUnboundedDecimals returns the unscaled values of the two decimals with the same frac.
+func alignUnboundedDecimals(from1, from2 *UnboundedDecimal) (v1, v2 *big.Int, frac int) {
+	v1, v2 = &from1.value, &from2.value
+	switch {
+	case from1.digitsFrac < from2.digitsFrac:
+		v1 = new(big.Int).Mul(v1, bigPow10(from2.digitsFrac-from1.digitsFrac))
+		frac = from2.digitsFrac
+	case from1.digitsFrac > from2.digitsFrac:
+		v2 = new(big.Int).Mul(v2, bigPow10(from1.digitsFrac-from2.digitsFrac))
+		frac = from1.digitsFrac
+	default:
+		frac = from1.digitsFrac
+	}
+	return
+}
+
+// UnboundedDecimalNeg reverses decimal's sign.
+func UnboundedDecimalNeg(from *UnboundedDecimal) *UnboundedDecimal {
+	to := new(UnboundedDecimal).Set(from)
+	to.value.Neg(&to.value)
+	return to
+}
+
+// UnboundedDecimalAdd adds two decimals, sets the result to 'to'.
+// Unlike DecimalAdd, `to` may be the same as `from1` or `from2`.
+func UnboundedDecimalAdd(from1, from2, to *UnboundedDecimal) error {
+	v1, v2, frac := alignUnboundedDecimals(from1, from2)
+	to.value.Add(v1, v2)
+	to.digitsFrac = frac
+	return nil
+}
+
+// UnboundedDecimalSub subs one decimal from another, sets the result to 'to'.
+// Unlike DecimalSub, `to` may be the same as `from1` or `from2`.
+func UnboundedDecimalSub(from1, from2, to *UnboundedDecimal) error {
+	v1, v2, frac := alignUnboundedDecimals(from1, from2)
+	to.value.Sub(v1, v2)
+	to.digitsFrac = frac
+	return nil
+}
+
+// UnboundedDecimalMul multiplies two decimals, sets the result to 'to'.
+// The frac of the product is the sum of the fracs of the factors,
+// `to` may be the same as `from1` or `from2`.
+func UnboundedDecimalMul(from1, from2, to *UnboundedDecimal) error {
+	frac := from1.digitsFrac + from2.digitsFrac
+	to.value.Mul(&from1.value, &from2.value)
+	to.digitsFrac = frac
+	return nil
+}
+
+// UnboundedDecimalDiv does division of two decimals.
+//
+// from1    - dividend
+// from2    - divisor
+// to       - quotient, it may be the same as from1 or from2
+// fracIncr - increment of fraction
+//
+// Like DecimalDiv, the quotient has the frac of the dividend plus fracIncr, at most
+// maxDecimalScale, and it is rounded half up.
+func UnboundedDecimalDiv(from1, from2, to *UnboundedDecimal, fracIncr int) error {
+	if from2.IsZero() {
+		return ErrDivByZero
+	}
+	frac := myMax(myMin(from1.digitsFrac+fracIncr, maxDecimalScale), 0)
+
+	// from1 / from2 = (v1 * 10^shift / v2) * 10^-(frac+1),
+	// one more digit is kept for rounding.
+	shift := frac + 1 + from2.digitsFrac - from1.digitsFrac
+	num := new(big.Int).Set(&from1.value)
+	den := new(big.Int).Set(&from2.value)
+	if shift >= 0 {
+		num.Mul(num, bigPow10(shift))
+	} else {
+		den.Mul(den, bigPow10(-shift))
+	}
+	to.value.Quo(num, den)
+	to.digitsFrac = frac + 1
+	return to.Round(to, frac, ModeHalfUp)
+}
+
+/*
+UnboundedDecimalMod does modulus of two decimals.
+
+	    from1   - dividend
+	    from2   - divisor
+	    to      - modulus, it may be the same as from1 or from2
+
+	The modulus has the same definition as DecimalMod:
+
+	   0 <= |R| < |M|
+	   sign R == sign M
+	   R = M - k*N, where k is integer
+*/
+func UnboundedDecimalMod(from1, from2, to *UnboundedDecimal) error {
+	if from2.IsZero() {
+		return ErrDivByZero
+	}
+	v1, v2, frac := alignUnboundedDecimals(from1, from2)
+	to.value.Rem(v1, v2)
+	to.digitsFrac = frac
+	return nil
+}
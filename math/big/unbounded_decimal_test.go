@@ -0,0 +1,204 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gxbig
+
+import (
+	"strings"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+var hugeNumber = strings.Repeat("1234567890", 10) + "." + strings.Repeat("9", 50)
+
+func TestUnboundedFromString(t *testing.T) {
+	tests := []struct {
+		input  string
+		output string
+		err    error
+	}{
+		{"123.123", "123.123", nil},
+		{"00123.1230", "123.1230", nil},
+		{"-.5", "-0.5", nil},
+		{".00012345", "0.00012345", nil},
+		{"-0", "0", nil},
+		{"1e001", "10", nil},
+		{"1.5e-3", "0.0015", nil},
+		{"123E5", "12300000", nil},
+		{"1e", "1", ErrTruncated},
+		{"", "0", ErrBadNumber},
+		{"abc", "0", ErrBadNumber},
+		{"1e65536", "1" + strings.Repeat("0", 65536), nil},
+		{"1E1000000000", "0", ErrOverflow},
+		{"1E-1000000000", "0", ErrOverflow},
+		{hugeNumber, hugeNumber, nil},
+		{"-" + hugeNumber, "-" + hugeNumber, nil},
+	}
+	for _, ca := range tests {
+		var dec UnboundedDecimal
+		err := dec.FromString(ca.input)
+		assert.Equal(t, ca.err, err, ca.input)
+		assert.Equal(t, ca.output, dec.String(), ca.input)
+	}
+
+	var dec Decimal
+	assert.Equal(t, ErrOverflow, dec.FromString(hugeNumber))
+}
+
+func TestUnboundedToDecimal(t *testing.T) {
+	var dec UnboundedDecimal
+	assert.Nil(t, dec.FromString("-123456789.987654321"))
+	d, err := dec.ToDecimal()
+	assert.Nil(t, err)
+	assert.Equal(t, "-123456789.987654321", string(d.ToBytes()))
+	assert.Equal(t, "-123456789.987654321", NewUnboundedDecFromDecimal(d).String())
+
+	assert.Nil(t, dec.FromString(hugeNumber))
+	d, err = dec.ToDecimal()
+	assert.Equal(t, ErrOverflow, err)
+	assert.Equal(t, strings.Repeat("9", 81), d.String())
+	assert.Nil(t, dec.FromString("-"+hugeNumber))
+	d, err = dec.ToDecimal()
+	assert.Equal(t, ErrOverflow, err)
+	assert.Equal(t, "-"+strings.Repeat("9", 81), d.String())
+	assert.Nil(t, dec.FromString(hugeNumber))
+
+	i, err := dec.ToInt()
+	assert.Equal(t, ErrOverflow, err)
+	assert.Equal(t, int64(9223372036854775807), i)
+	i, err = NewUnboundedDecFromInt(-42).ToInt()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(-42), i)
+}
+
+func TestUnboundedArithmetic(t *testing.T) {
+	tests := []struct {
+		a, b               string
+		add, sub, mul, mod string
+		div                string
+	}{
+		{"12345", "1.23", "12346.23", "12343.77", "15184.35", "0.72", "10036.5854"},
+		{"-12345", "1.23", "-12343.77", "-12346.23", "-15184.35", "-0.72", "-10036.5854"},
+		{"1", "3", "4", "-2", "3", "1", "0.3333"},
+		{"2", "3", "5", "-1", "6", "2", "0.6667"},
+		{"-2", "3", "1", "-5", "-6", "-2", "-0.6667"},
+		{"99999999999999999999999999999999999999999999999999999999999999999999999999999999999", "1",
+			"100000000000000000000000000000000000000000000000000000000000000000000000000000000000",
+			"99999999999999999999999999999999999999999999999999999999999999999999999999999999998",
+			"99999999999999999999999999999999999999999999999999999999999999999999999999999999999",
+			"0",
+			"99999999999999999999999999999999999999999999999999999999999999999999999999999999999.0000"},
+	}
+	for _, ca := range tests {
+		var a, b, to UnboundedDecimal
+		assert.Nil(t, a.FromString(ca.a))
+		assert.Nil(t, b.FromString(ca.b))
+
+		assert.Nil(t, UnboundedDecimalAdd(&a, &b, &to))
+		assert.Equal(t, ca.add, to.String())
+		assert.Nil(t, UnboundedDecimalSub(&a, &b, &to))
+		assert.Equal(t, ca.sub, to.String())
+		assert.Nil(t, UnboundedDecimalMul(&a, &b, &to))
+		assert.Equal(t, ca.mul, to.String())
+		assert.Nil(t, UnboundedDecimalMod(&a, &b, &to))
+		assert.Equal(t, ca.mod, to.String())
+		assert.Nil(t, UnboundedDecimalDiv(&a, &b, &to, DivFracIncr))
+		assert.Equal(t, ca.div, to.String())
+	}
+
+	// aliasing the result with the operands.
+	a := NewUnboundedDecFromInt(3)
+	assert.Nil(t, UnboundedDecimalAdd(a, a, a))
+	assert.Nil(t, UnboundedDecimalMul(a, a, a))
+	assert.Equal(t, "36", a.String())
+
+	var zero UnboundedDecimal
+	assert.Equal(t, ErrDivByZero, UnboundedDecimalDiv(a, &zero, a, DivFracIncr))
+	assert.Equal(t, ErrDivByZero, UnboundedDecimalMod(a, &zero, a))
+	assert.Equal(t, "-36", UnboundedDecimalNeg(a).String())
+}
+
+func TestUnboundedCompare(t *testing.T) {
+	tests := []struct {
+		a   string
+		b   string
+		cmp int
+	}{
+		{"12", "13", -1},
+		{"-10", "10", -1},
+		{"-12", "-13", 1},
+		{"4", "4.000", 0},
+		{"-1.1", "-1.2", 1},
+		{hugeNumber, "1e99", 1},
+		{hugeNumber, "1e100", -1},
+	}
+	for _, ca := range tests {
+		var a, b UnboundedDecimal
+		assert.Nil(t, a.FromString(ca.a))
+		assert.Nil(t, b.FromString(ca.b))
+		assert.Equal(t, ca.cmp, a.Compare(&b), ca.a+" "+ca.b)
+	}
+}
+
+func TestUnboundedRound(t *testing.T) {
+	tests := []struct {
+		input  string
+		scale  int
+		mode   RoundMode
+		output string
+		err    error
+	}{
+		{"123456789.987654321", 1, ModeHalfUp, "123456790.0", nil},
+		{"15.4", -1, ModeHalfUp, "20", nil},
+		{"-15.5", 0, ModeHalfToEven, "-16", nil},
+		{"-2.5", 0, ModeHalfToEven, "-2", nil},
+		{"-2.5", 0, ModeHalfDown, "-2", nil},
+		{"-2.1", 0, ModeCeiling, "-2", nil},
+		{"-2.1", 0, ModeFloor, "-3", nil},
+		{"0.001", -2, ModeUp, "100", nil},
+		{"15.1", 3, ModeUnnecessary, "15.100", nil},
+		{"15.1", 0, ModeUnnecessary, "15.1", ErrRoundingNecessary},
+		{hugeNumber, 0, ModeHalfUp, strings.Repeat("1234567890", 9) + "1234567891", nil},
+		{"1.5", 1 << 20, ModeHalfUp, "1.5", ErrOverflow},
+		{"1.5", -1 << 20, ModeHalfUp, "1.5", ErrOverflow},
+	}
+	for _, ca := range tests {
+		var dec UnboundedDecimal
+		assert.Nil(t, dec.FromString(ca.input))
+		err := dec.Round(&dec, ca.scale, ca.mode)
+		assert.Equal(t, ca.err, err, ca.input)
+		assert.Equal(t, ca.output, dec.String(), ca.input)
+	}
+}
+
+func TestUnboundedShift(t *testing.T) {
+	var dec UnboundedDecimal
+	assert.Nil(t, dec.FromString("-1.5"))
+	assert.Nil(t, dec.Shift(3))
+	assert.Equal(t, "-1500", dec.String())
+	assert.Nil(t, dec.Shift(-5))
+	assert.Equal(t, "-0.01500", dec.String())
+
+	// a huge shift would materialize its digits, it overflows and leaves the decimal unchanged.
+	assert.Equal(t, ErrOverflow, dec.Shift(1<<30))
+	assert.Equal(t, ErrOverflow, dec.Shift(-1<<30))
+	assert.Equal(t, "-0.01500", dec.String())
+	assert.Nil(t, dec.Shift(maxUnboundedExponent))
+}